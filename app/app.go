@@ -1,10 +1,18 @@
 package app
 
 import (
+	"ecommerce-api/bruteforce"
 	"ecommerce-api/database"
+	"ecommerce-api/denylist"
 	"ecommerce-api/handler"
+	"ecommerce-api/helper"
+	"ecommerce-api/mailer"
 	"ecommerce-api/middleware"
+	"ecommerce-api/oidc"
+	"ecommerce-api/payment"
+	"ecommerce-api/payout"
 	"ecommerce-api/service"
+	"ecommerce-api/storage"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
@@ -24,17 +32,68 @@ func NewApp() *App {
 	}
 
 	config := NewConfig()
+	if err := config.Settlement.Validate(); err != nil {
+		panic(err)
+	}
+	jwtKeySet := config.NewJwtKeySet()
 	database := database.NewDatabase(config.DatabaseUrl)
 	validator := validator.New()
-	authService := service.NewAuthService(database, config.Jwt.SigningKey.([]byte))
-	userService := service.NewUserService(database)
-	productService := service.NewProductService(database, authService)
-	authHandler := handler.NewAuthHandler(database, validator, authService, config.Jwt.SigningKey.([]byte))
-	userHandler := handler.NewUserHandler(database, validator, authService, userService)
+	mailer, err := mailer.NewMailer(config.Mailer)
+	if err != nil {
+		panic(err)
+	}
+	tokenDenylist := denylist.NewDenylist(newDenylistStore(config.TokenDenylistStore, database), helper.AccessTokenLifetime)
+	twoFactorService := service.NewTwoFactorService(database, config.TotpIssuer)
+	authService := service.NewAuthService(database, twoFactorService, jwtKeySet, tokenDenylist, service.LoginProtection{
+		Guard:       bruteforce.NewGuard(newLoginAttemptStore(config.LoginProtection.Store, database)),
+		EmailPolicy: config.LoginProtection.EmailPolicy,
+		IpPolicy:    config.LoginProtection.IpPolicy,
+	})
+	avatarService := service.NewAvatarService(database, storage.NewStorage(config.Storage), config.Avatar)
+	userService := service.NewUserService(database, tokenDenylist, mailer, avatarService, config.AppUrl)
+	if err := userService.GrantAdminRoles(config.AdminEmails); err != nil {
+		panic(err)
+	}
+	paymentProvider, err := payment.NewProvider(config.Payment, database)
+	if err != nil {
+		panic(err)
+	}
+	walletService := service.NewWalletService(database, paymentProvider, config.Wallet)
+	storeLedgerService := service.NewStoreLedgerService(database, config.Settlement)
+	exchangeRateService := service.NewExchangeRateService(database)
+	addressService := service.NewAddressService(database)
+	categoryService := service.NewCategoryService(database)
+	productService := service.NewProductService(database, authService, walletService, storeLedgerService, exchangeRateService, addressService, categoryService)
+	transactionService := service.NewTransactionService(database, walletService, storeLedgerService)
+	transferService := service.NewTransferService(database, walletService, config.Transfer)
+	payoutProvider, err := payout.NewProvider(config.PayoutProvider)
+	if err != nil {
+		panic(err)
+	}
+	withdrawalService := service.NewWithdrawalService(database, payoutProvider)
+	sessionService := service.NewSessionService(database, tokenDenylist)
+	storeApiKeyService := service.NewStoreApiKeyService(database)
+	oidcService := service.NewOidcService(database, authService, newOidcProviders(config.Oidc))
+	authHandler := handler.NewAuthHandler(database, validator, config.Password, authService, userService)
+	userHandler := handler.NewUserHandler(database, validator, config.Password, authService, userService)
 	storeHandler := handler.NewStoreHandler(database, validator)
 	productHandler := handler.NewProductHandler(database, validator, productService)
-	transactionHandler := handler.NewTransactionHandler(database, validator)
-	authMiddleware := middleware.NewAuthMiddleware(config.Jwt)
+	transactionHandler := handler.NewTransactionHandler(database, validator, transactionService)
+	sessionHandler := handler.NewSessionHandler(database, validator, sessionService)
+	twoFactorHandler := handler.NewTwoFactorHandler(database, validator, twoFactorService)
+	jwksHandler := handler.NewJwksHandler(jwtKeySet)
+	oidcHandler := handler.NewOidcHandler(database, validator, oidcService)
+	storeApiKeyHandler := handler.NewStoreApiKeyHandler(database, validator, storeApiKeyService)
+	walletHandler := handler.NewWalletHandler(database, validator, walletService)
+	storeLedgerHandler := handler.NewStoreLedgerHandler(database, validator, storeLedgerService)
+	withdrawalHandler := handler.NewWithdrawalHandler(database, validator, withdrawalService)
+	transferHandler := handler.NewTransferHandler(database, validator, transferService)
+	exchangeRateHandler := handler.NewExchangeRateHandler(database, validator, exchangeRateService)
+	addressHandler := handler.NewAddressHandler(database, validator, addressService)
+	categoryHandler := handler.NewCategoryHandler(database, validator, categoryService)
+	avatarHandler := handler.NewAvatarHandler(database, validator, avatarService)
+	authMiddleware := middleware.NewAuthMiddleware(config.Jwt, database, tokenDenylist)
+	apiKeyMiddleware := middleware.NewApiKeyMiddleware(database)
 	instance := echo.New()
 	SetupRoute(
 		instance,
@@ -43,9 +102,27 @@ func NewApp() *App {
 		storeHandler,
 		productHandler,
 		transactionHandler,
+		sessionHandler,
+		twoFactorHandler,
+		jwksHandler,
+		oidcHandler,
+		storeApiKeyHandler,
+		walletHandler,
+		storeLedgerHandler,
+		withdrawalHandler,
+		transferHandler,
+		exchangeRateHandler,
+		addressHandler,
+		categoryHandler,
+		avatarHandler,
 		authMiddleware,
+		apiKeyMiddleware,
 	)
 
+	if config.Storage.Driver != "s3" {
+		instance.Static(storage.LocalPublicPath, config.Storage.LocalDir)
+	}
+
 	return &App{
 		Instance: instance,
 		Config:   config,
@@ -53,6 +130,28 @@ func NewApp() *App {
 	}
 }
 
+func newLoginAttemptStore(driver string, database *database.Database) bruteforce.Store {
+	if driver == "postgres" {
+		return bruteforce.NewPostgresStore(database)
+	}
+	return bruteforce.NewMemoryStore()
+}
+
+func newDenylistStore(driver string, database *database.Database) denylist.Store {
+	if driver == "postgres" {
+		return denylist.NewPostgresStore(database)
+	}
+	return denylist.NewMemoryStore()
+}
+
+func newOidcProviders(configs []oidc.Config) []*oidc.Provider {
+	var providers []*oidc.Provider
+	for _, config := range configs {
+		providers = append(providers, oidc.NewProvider(config, nil))
+	}
+	return providers
+}
+
 func (app *App) Start() {
 	app.Instance.Logger.Fatal(app.Instance.Start(":" + app.Config.Port))
 }
@@ -1,8 +1,17 @@
 package app
 
 import (
+	"ecommerce-api/bruteforce"
 	"ecommerce-api/helper"
+	"ecommerce-api/mailer"
+	"ecommerce-api/oidc"
+	"ecommerce-api/payment"
+	"ecommerce-api/service"
+	"ecommerce-api/storage"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	echojwt "github.com/labstack/echo-jwt/v4"
@@ -10,20 +19,207 @@ import (
 )
 
 type Config struct {
-	DatabaseUrl string
-	Port        string
-	Jwt         echojwt.Config
+	DatabaseUrl        string
+	Port               string
+	AppUrl             string
+	TotpIssuer         string
+	JwtKeyDir          string
+	JwtSigningKeyID    string
+	Jwt                echojwt.Config
+	Mailer             mailer.Config
+	Password           helper.PasswordPolicy
+	Oidc               []oidc.Config
+	LoginProtection    LoginProtectionConfig
+	TokenDenylistStore string
+	Settlement         service.SettlementPolicy
+	PayoutProvider     string
+	Payment            payment.Config
+	Wallet             service.WalletPolicy
+	Transfer           service.TransferPolicy
+	Storage            storage.Config
+	Avatar             service.AvatarPolicy
+	AdminEmails        []string
+}
+
+type LoginProtectionConfig struct {
+	Store       string
+	EmailPolicy bruteforce.Policy
+	IpPolicy    bruteforce.Policy
 }
 
 func NewConfig() *Config {
 	return &Config{
-		DatabaseUrl: os.Getenv("DATABASE_URL"),
-		Port:        os.Getenv("PORT"),
+		DatabaseUrl:     os.Getenv("DATABASE_URL"),
+		Port:            os.Getenv("PORT"),
+		AppUrl:          os.Getenv("APP_URL"),
+		TotpIssuer:      getEnv("TOTP_ISSUER", "Ecommerce API"),
+		JwtKeyDir:       os.Getenv("JWT_KEY_DIR"),
+		JwtSigningKeyID: os.Getenv("JWT_SIGNING_KEY_ID"),
 		Jwt: echojwt.Config{
 			NewClaimsFunc: func(c echo.Context) jwt.Claims {
 				return new(helper.JwtCustomClaims)
 			},
 			SigningKey: []byte(os.Getenv("JWT_KEY")),
 		},
+		Mailer: mailer.Config{
+			Driver:       os.Getenv("MAILER_DRIVER"),
+			From:         os.Getenv("MAILER_FROM"),
+			SmtpHost:     os.Getenv("SMTP_HOST"),
+			SmtpPort:     os.Getenv("SMTP_PORT"),
+			SmtpUsername: os.Getenv("SMTP_USERNAME"),
+			SmtpPassword: os.Getenv("SMTP_PASSWORD"),
+			LogPath:      os.Getenv("MAILER_LOG_PATH"),
+		},
+		Password: helper.PasswordPolicy{
+			MinLength:        getEnvInt("PASSWORD_MIN_LENGTH", 8),
+			RequireUppercase: getEnvBool("PASSWORD_REQUIRE_UPPERCASE", false),
+			RequireLowercase: getEnvBool("PASSWORD_REQUIRE_LOWERCASE", false),
+			RequireDigit:     getEnvBool("PASSWORD_REQUIRE_DIGIT", false),
+			RequireSymbol:    getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+		},
+		Oidc:               newOidcConfigs(),
+		TokenDenylistStore: getEnv("TOKEN_DENYLIST_STORE", "memory"),
+		Settlement: service.SettlementPolicy{
+			HoldPeriod:    getEnvDuration("SETTLEMENT_HOLD_PERIOD", 0),
+			CommissionBps: int64(getEnvInt("PLATFORM_COMMISSION_BPS", 0)),
+		},
+		PayoutProvider: getEnv("PAYOUT_PROVIDER", "fake"),
+		Payment: payment.Config{
+			Driver:    os.Getenv("PAYMENT_PROVIDER"),
+			AllowFake: getEnvBool("PAYMENT_ALLOW_FAKE", false),
+		},
+		Wallet: service.WalletPolicy{
+			TopUpDailyLimit: int64(getEnvInt("WALLET_TOPUP_DAILY_LIMIT", 1000000)),
+		},
+		Transfer: service.TransferPolicy{
+			DailyLimit: int64(getEnvInt("TRANSFER_DAILY_LIMIT", 1000000)),
+		},
+		Storage: storage.Config{
+			Driver:         getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:       getEnv("STORAGE_LOCAL_DIR", "uploads"),
+			PublicUrl:      os.Getenv("STORAGE_PUBLIC_URL"),
+			S3Endpoint:     os.Getenv("S3_ENDPOINT"),
+			S3Region:       getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:       os.Getenv("S3_BUCKET"),
+			S3AccessKey:    os.Getenv("S3_ACCESS_KEY"),
+			S3SecretKey:    os.Getenv("S3_SECRET_KEY"),
+			S3UsePathStyle: getEnvBool("S3_USE_PATH_STYLE", false),
+		},
+		Avatar: service.AvatarPolicy{
+			MaxSize:      int64(getEnvInt("AVATAR_MAX_SIZE", 5*1024*1024)),
+			MaxDimension: getEnvInt("AVATAR_MAX_DIMENSION", 4096),
+		},
+		AdminEmails: newAdminEmails(),
+		LoginProtection: LoginProtectionConfig{
+			Store: getEnv("LOGIN_ATTEMPT_STORE", "memory"),
+			EmailPolicy: bruteforce.Policy{
+				FreeAttempts:     getEnvInt("LOGIN_EMAIL_FREE_ATTEMPTS", 3),
+				BaseDelay:        getEnvDuration("LOGIN_EMAIL_BASE_DELAY", time.Second),
+				MaxDelay:         getEnvDuration("LOGIN_EMAIL_MAX_DELAY", 5*time.Minute),
+				LockoutThreshold: getEnvInt("LOGIN_EMAIL_LOCKOUT_THRESHOLD", 10),
+				LockoutDuration:  getEnvDuration("LOGIN_EMAIL_LOCKOUT_DURATION", 30*time.Minute),
+				Window:           getEnvDuration("LOGIN_EMAIL_WINDOW", 24*time.Hour),
+			},
+			IpPolicy: bruteforce.Policy{
+				FreeAttempts:     getEnvInt("LOGIN_IP_FREE_ATTEMPTS", 20),
+				BaseDelay:        getEnvDuration("LOGIN_IP_BASE_DELAY", time.Second),
+				MaxDelay:         getEnvDuration("LOGIN_IP_MAX_DELAY", 5*time.Minute),
+				LockoutThreshold: getEnvInt("LOGIN_IP_LOCKOUT_THRESHOLD", 100),
+				LockoutDuration:  getEnvDuration("LOGIN_IP_LOCKOUT_DURATION", time.Hour),
+				Window:           getEnvDuration("LOGIN_IP_WINDOW", time.Hour),
+			},
+		},
+	}
+}
+
+func newOidcConfigs() []oidc.Config {
+	var configs []oidc.Config
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		configs = append(configs, oidc.Config{
+			Name:         name,
+			IssuerUrl:    os.Getenv(prefix + "ISSUER_URL"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectUrl:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		})
+	}
+
+	return configs
+}
+
+func newAdminEmails() []string {
+	var emails []string
+
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		email = helper.NormalizeEmail(email)
+		if email == "" {
+			continue
+		}
+
+		emails = append(emails, email)
+	}
+
+	return emails
+}
+
+func (c *Config) NewJwtKeySet() *helper.JwtKeySet {
+	secret := c.Jwt.SigningKey.([]byte)
+	keySet := helper.NewHmacJwtKeySet(secret)
+
+	if c.JwtKeyDir != "" {
+		var err error
+		keySet, err = helper.LoadJwtKeySet(c.JwtKeyDir, c.JwtSigningKeyID)
+		if err != nil {
+			panic(err)
+		}
+
+		if len(secret) > 0 {
+			if err := keySet.AddVerifyKey(helper.NewHmacJwtKey(secret)); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	c.Jwt.KeyFunc = keySet.Keyfunc
+
+	return keySet
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
 	}
+	return value
 }
@@ -3,6 +3,7 @@ package app
 import (
 	"ecommerce-api/handler"
 	"ecommerce-api/middleware"
+	"ecommerce-api/model"
 
 	"github.com/labstack/echo/v4"
 )
@@ -14,36 +15,117 @@ func SetupRoute(
 	storeHandler *handler.StoreHandler,
 	productHandler *handler.ProductHandler,
 	transactionHandler *handler.TransactionHandler,
+	sessionHandler *handler.SessionHandler,
+	twoFactorHandler *handler.TwoFactorHandler,
+	jwksHandler *handler.JwksHandler,
+	oidcHandler *handler.OidcHandler,
+	storeApiKeyHandler *handler.StoreApiKeyHandler,
+	walletHandler *handler.WalletHandler,
+	storeLedgerHandler *handler.StoreLedgerHandler,
+	withdrawalHandler *handler.WithdrawalHandler,
+	transferHandler *handler.TransferHandler,
+	exchangeRateHandler *handler.ExchangeRateHandler,
+	addressHandler *handler.AddressHandler,
+	categoryHandler *handler.CategoryHandler,
+	avatarHandler *handler.AvatarHandler,
 	authMiddleware *middleware.AuthMiddleware,
+	apiKeyMiddleware *middleware.ApiKeyMiddleware,
 ) {
+	e.GET("/.well-known/jwks.json", jwksHandler.Get)
+
 	auth := e.Group("/auth")
 	auth.POST("/login", authHandler.Login)
+	auth.POST("/login/2fa", authHandler.VerifyTwoFactor)
 	auth.POST("/logout", authHandler.Logout, authMiddleware.LoginOnly)
 	auth.GET("/refresh", authHandler.Refresh)
+	auth.GET("/oidc/:provider/login", oidcHandler.Login)
+	auth.GET("/oidc/:provider/callback", oidcHandler.Callback)
+	auth.POST("/password/forgot", authHandler.ForgotPassword)
+	auth.POST("/password/reset", authHandler.ResetPassword)
 
 	user := e.Group("/user")
-	user.GET("", userHandler.GetAll)
+	user.GET("", userHandler.GetAll, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
 	user.POST("", userHandler.Register)
-	user.GET("/:email", userHandler.GetByEmail)
+	user.POST("/verify", userHandler.VerifyEmail)
+	user.GET("/:email", userHandler.GetByEmail, authMiddleware.LoginOptional)
 	user.GET("/current", userHandler.GetCurrent, authMiddleware.LoginOnly)
 	user.PUT("/current", userHandler.UpdateCurrent, authMiddleware.LoginOnly)
-	user.GET("/current/transaction", transactionHandler.GetAllCurrentUserTransaction, authMiddleware.LoginOnly)
+	user.DELETE("/current", userHandler.DeleteCurrent, authMiddleware.LoginOnly)
+	user.PUT("/current/avatar", avatarHandler.UploadCurrent, authMiddleware.LoginOnly)
+	user.DELETE("/current/avatar", avatarHandler.DeleteCurrent, authMiddleware.LoginOnly)
+	user.GET("/current/export", userHandler.ExportCurrent, authMiddleware.LoginOnly)
+	user.PUT("/current/password", userHandler.ChangeCurrentPassword, authMiddleware.LoginOnly)
+	user.GET("/current/transaction", transactionHandler.GetAllCurrentUserTransaction, authMiddleware.LoginOnly, authMiddleware.BuyerOnly)
+	user.POST("/current/wallet/topup", walletHandler.TopUpCurrent, authMiddleware.LoginOnly)
+	user.GET("/current/wallet/topup", walletHandler.GetAllCurrentTopUp, authMiddleware.LoginOnly)
+	user.POST("/current/wallet/topup/:id/confirm", walletHandler.ConfirmCurrentTopUp, authMiddleware.LoginOnly)
+	user.GET("/current/wallet/ledger", walletHandler.GetAllCurrentLedgerEntry, authMiddleware.LoginOnly)
+	user.GET("/current/transfer", transferHandler.GetAllCurrentUserTransfer, authMiddleware.LoginOnly)
+	user.POST("/current/transfer", transferHandler.CreateCurrentUserTransfer, authMiddleware.LoginOnly)
+	user.GET("/current/address", addressHandler.GetAllCurrentUserAddress, authMiddleware.LoginOnly)
+	user.POST("/current/address", addressHandler.CreateCurrentUserAddress, authMiddleware.LoginOnly)
+	user.GET("/current/address/:id", addressHandler.GetCurrentUserAddress, authMiddleware.LoginOnly)
+	user.PUT("/current/address/:id", addressHandler.UpdateCurrentUserAddress, authMiddleware.LoginOnly)
+	user.DELETE("/current/address/:id", addressHandler.DeleteCurrentUserAddress, authMiddleware.LoginOnly)
+	user.POST("/current/verification", userHandler.ResendCurrentVerificationEmail, authMiddleware.LoginOnly)
+	user.POST("/current/2fa", twoFactorHandler.Enroll, authMiddleware.LoginOnly)
+	user.POST("/current/2fa/confirm", twoFactorHandler.Confirm, authMiddleware.LoginOnly)
+	user.DELETE("/current/2fa", twoFactorHandler.Disable, authMiddleware.LoginOnly)
+	user.GET("/current/sessions", sessionHandler.GetAllCurrentUserSession, authMiddleware.LoginOnly)
+	user.DELETE("/current/sessions", sessionHandler.RevokeAllCurrentUserSession, authMiddleware.LoginOnly)
+	user.DELETE("/current/sessions/:id", sessionHandler.RevokeCurrentUserSession, authMiddleware.LoginOnly)
+	user.GET("/lockout", userHandler.GetAllLockout, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	user.PUT("/:email/roles", userHandler.UpdateRoles, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	user.DELETE("/:email/lockout", userHandler.Unlock, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	user.POST("/:email/wallet/adjustment", walletHandler.Adjust, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	user.PUT("/:email/ban", userHandler.Ban, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	user.DELETE("/:email/ban", userHandler.Unban, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
 
 	store := e.Group("/store")
 	store.GET("", storeHandler.GetAll)
 	store.GET("/:id", storeHandler.GetByID)
-	store.GET("/current", storeHandler.GetCurrent, authMiddleware.LoginOnly)
+	store.POST("/:id/opening-balance/release", storeLedgerHandler.ReleaseOpeningBalance, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	store.GET("/current", storeHandler.GetCurrent, authMiddleware.LoginOnly, authMiddleware.SellerOnly)
 	store.POST("/current", storeHandler.CreateCurrentUserStore, authMiddleware.LoginOnly)
-	store.PUT("/current", storeHandler.UpdateCurrent, authMiddleware.LoginOnly)
-	store.POST("/current/product", productHandler.CreateCurrentStoreProduct, authMiddleware.LoginOnly)
-	store.PUT("/current/product/:id", productHandler.UpdateCurrentStoreProduct, authMiddleware.LoginOnly)
+	store.PUT("/current", storeHandler.UpdateCurrent, authMiddleware.LoginOnly, authMiddleware.SellerOnly)
+	store.GET("/current/balance", storeLedgerHandler.GetCurrentBalance, authMiddleware.LoginOnly, authMiddleware.SellerOnly)
+	store.GET("/current/earnings", storeLedgerHandler.GetCurrentEarnings, authMiddleware.LoginOnly, authMiddleware.SellerOnly)
+	store.GET("/current/withdrawal", withdrawalHandler.GetAllCurrentStoreWithdrawal, authMiddleware.LoginOnly, authMiddleware.SellerOnly)
+	store.POST("/current/withdrawal", withdrawalHandler.RequestCurrentStoreWithdrawal, authMiddleware.LoginOnly, authMiddleware.SellerOnly)
+	store.GET("/current/product", productHandler.GetAllCurrentStoreProduct, apiKeyMiddleware.AllowScope(model.ApiKeyScopeProductRead, authMiddleware.LoginOnly, authMiddleware.SellerOnly))
+	store.POST("/current/product", productHandler.CreateCurrentStoreProduct, apiKeyMiddleware.AllowScope(model.ApiKeyScopeProductWrite, authMiddleware.LoginOnly, authMiddleware.SellerOnly))
+	store.PUT("/current/product/:id", productHandler.UpdateCurrentStoreProduct, apiKeyMiddleware.AllowScope(model.ApiKeyScopeProductWrite, authMiddleware.LoginOnly, authMiddleware.SellerOnly))
+	store.GET("/current/api-key", storeApiKeyHandler.GetAllCurrentStoreApiKey, authMiddleware.LoginOnly, authMiddleware.SellerOnly)
+	store.POST("/current/api-key", storeApiKeyHandler.CreateCurrentStoreApiKey, authMiddleware.LoginOnly, authMiddleware.SellerOnly)
+	store.DELETE("/current/api-key/:id", storeApiKeyHandler.RevokeCurrentStoreApiKey, authMiddleware.LoginOnly, authMiddleware.SellerOnly)
 
 	product := e.Group("/product")
 	product.GET("", productHandler.GetAll)
+	product.GET("/search", productHandler.Search)
 	product.GET("/:id", productHandler.GetByID)
-	product.POST("/:id/buy", productHandler.Buy, authMiddleware.LoginOnly)
+	product.POST("/:id/buy", productHandler.Buy, authMiddleware.LoginOnly, authMiddleware.BuyerOnly)
+
+	category := e.Group("/category")
+	category.GET("", categoryHandler.GetTree)
+	category.POST("", categoryHandler.Create, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	category.GET("/:id", categoryHandler.GetByID)
+	category.PUT("/:id", categoryHandler.Update, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	category.DELETE("/:id", categoryHandler.Delete, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	category.GET("/:id/product", categoryHandler.GetAllProduct)
 
 	transaction := e.Group("/transaction")
-	transaction.GET("", transactionHandler.GetAll)
-	transaction.GET("/:id", transactionHandler.GetByID)
+	transaction.GET("", transactionHandler.GetAll, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	transaction.GET("/:id", transactionHandler.GetByID, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	transaction.POST("/:id/refund", transactionHandler.Refund, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+
+	withdrawal := e.Group("/withdrawal")
+	withdrawal.GET("", withdrawalHandler.GetAll, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	withdrawal.POST("/:id/approve", withdrawalHandler.Approve, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	withdrawal.POST("/:id/reject", withdrawalHandler.Reject, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	withdrawal.POST("/:id/fail", withdrawalHandler.MarkFailed, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+
+	exchangeRate := e.Group("/exchange-rate")
+	exchangeRate.GET("", exchangeRateHandler.GetAll, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	exchangeRate.PUT("/:base/:quote", exchangeRateHandler.Set, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
+	exchangeRate.DELETE("/:base/:quote", exchangeRateHandler.Delete, authMiddleware.LoginOnly, authMiddleware.AdminOnly)
 }
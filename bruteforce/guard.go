@@ -0,0 +1,127 @@
+package bruteforce
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type Attempt struct {
+	Key           string
+	Failures      int
+	BlockedUntil  *time.Time
+	LastFailureAt *time.Time
+}
+
+type Store interface {
+	Get(key string) (Attempt, error)
+	RecordFailure(key string, now time.Time, windowStart time.Time) (Attempt, error)
+	Block(key string, until time.Time) error
+	Reset(key string) error
+}
+
+type Policy struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	Window           time.Duration
+}
+
+type LockedError struct {
+	Key        string
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("Too many failed attempts, retry after %s", e.RetryAfter)
+}
+
+type Guard struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewGuard(store Store) *Guard {
+	return &Guard{
+		store: store,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (g *Guard) Check(keys ...string) error {
+	now := g.now()
+
+	var lockedErr *LockedError
+	for _, key := range keys {
+		attempt, err := g.store.Get(key)
+		if err != nil {
+			return err
+		}
+
+		if attempt.BlockedUntil == nil || !attempt.BlockedUntil.After(now) {
+			continue
+		}
+
+		retryAfter := attempt.BlockedUntil.Sub(now)
+		if lockedErr == nil || retryAfter > lockedErr.RetryAfter {
+			lockedErr = &LockedError{
+				Key:        key,
+				RetryAfter: retryAfter,
+			}
+		}
+	}
+
+	if lockedErr != nil {
+		return lockedErr
+	}
+
+	return nil
+}
+
+func (g *Guard) Fail(key string, policy Policy) (Attempt, bool, error) {
+	now := g.now()
+
+	attempt, err := g.store.RecordFailure(key, now, now.Add(-policy.Window))
+	if err != nil {
+		return attempt, false, err
+	}
+
+	var blockedUntil time.Time
+	lockedOut := false
+
+	switch {
+	case policy.LockoutThreshold > 0 && attempt.Failures >= policy.LockoutThreshold:
+		blockedUntil = now.Add(policy.LockoutDuration)
+		lockedOut = attempt.Failures == policy.LockoutThreshold
+	case attempt.Failures > policy.FreeAttempts:
+		exponent := float64(attempt.Failures - policy.FreeAttempts - 1)
+		delay := time.Duration(float64(policy.BaseDelay) * math.Pow(2, exponent))
+		if delay > policy.MaxDelay || delay <= 0 {
+			delay = policy.MaxDelay
+		}
+		blockedUntil = now.Add(delay)
+	default:
+		return attempt, false, nil
+	}
+
+	if err := g.store.Block(key, blockedUntil); err != nil {
+		return attempt, false, err
+	}
+
+	attempt.BlockedUntil = &blockedUntil
+
+	return attempt, lockedOut, nil
+}
+
+func (g *Guard) Reset(keys ...string) error {
+	for _, key := range keys {
+		if err := g.store.Reset(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
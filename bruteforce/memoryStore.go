@@ -0,0 +1,107 @@
+package bruteforce
+
+import (
+	"sync"
+	"time"
+)
+
+const memoryStoreSweepInterval = time.Minute
+
+type memoryAttempt struct {
+	attempt Attempt
+	window  time.Duration
+}
+
+func (a memoryAttempt) isExpired(now time.Time) bool {
+	if a.attempt.BlockedUntil != nil && a.attempt.BlockedUntil.After(now) {
+		return false
+	}
+
+	return a.attempt.LastFailureAt == nil || !a.attempt.LastFailureAt.Add(a.window).After(now)
+}
+
+type MemoryStore struct {
+	mutex     sync.Mutex
+	attempts  map[string]memoryAttempt
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		attempts: map[string]memoryAttempt{},
+	}
+}
+
+func (s *MemoryStore) Get(key string) (Attempt, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, ok := s.attempts[key]
+	if !ok {
+		return Attempt{Key: key}, nil
+	}
+
+	if entry.isExpired(time.Now()) {
+		delete(s.attempts, key)
+		return Attempt{Key: key}, nil
+	}
+
+	return entry.attempt, nil
+}
+
+func (s *MemoryStore) RecordFailure(key string, now time.Time, windowStart time.Time) (Attempt, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweep(now)
+
+	entry, ok := s.attempts[key]
+	attempt := entry.attempt
+	if !ok || attempt.LastFailureAt == nil || attempt.LastFailureAt.Before(windowStart) {
+		attempt = Attempt{Key: key, BlockedUntil: attempt.BlockedUntil}
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = &now
+	s.attempts[key] = memoryAttempt{
+		attempt: attempt,
+		window:  now.Sub(windowStart),
+	}
+
+	return attempt, nil
+}
+
+func (s *MemoryStore) Block(key string, until time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry := s.attempts[key]
+	entry.attempt.Key = key
+	entry.attempt.BlockedUntil = &until
+	s.attempts[key] = entry
+
+	return nil
+}
+
+func (s *MemoryStore) Reset(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.attempts, key)
+
+	return nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryStoreSweepInterval {
+		return
+	}
+
+	for key, entry := range s.attempts {
+		if entry.isExpired(now) {
+			delete(s.attempts, key)
+		}
+	}
+
+	s.lastSweep = now
+}
@@ -0,0 +1,68 @@
+package bruteforce
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/model"
+	"time"
+)
+
+type PostgresStore struct {
+	database *database.Database
+}
+
+func NewPostgresStore(database *database.Database) *PostgresStore {
+	return &PostgresStore{
+		database: database,
+	}
+}
+
+func (s *PostgresStore) Get(key string) (Attempt, error) {
+	loginAttempt := model.LoginAttempt{
+		Key: key,
+	}
+	if err := loginAttempt.GetByKey(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return Attempt{Key: key}, nil
+		}
+		return Attempt{Key: key}, err
+	}
+
+	return toAttempt(loginAttempt), nil
+}
+
+func (s *PostgresStore) RecordFailure(key string, now time.Time, windowStart time.Time) (Attempt, error) {
+	loginAttempt := model.LoginAttempt{
+		Key: key,
+	}
+	if err := loginAttempt.RecordFailure(s.database.Conn, now, windowStart); err != nil {
+		return Attempt{Key: key}, err
+	}
+
+	return toAttempt(loginAttempt), nil
+}
+
+func (s *PostgresStore) Block(key string, until time.Time) error {
+	loginAttempt := model.LoginAttempt{
+		Key:          key,
+		BlockedUntil: &until,
+	}
+
+	return loginAttempt.Block(s.database.Conn)
+}
+
+func (s *PostgresStore) Reset(key string) error {
+	loginAttempt := model.LoginAttempt{
+		Key: key,
+	}
+
+	return loginAttempt.Delete(s.database.Conn)
+}
+
+func toAttempt(loginAttempt model.LoginAttempt) Attempt {
+	return Attempt{
+		Key:           loginAttempt.Key,
+		Failures:      loginAttempt.Failures,
+		BlockedUntil:  loginAttempt.BlockedUntil,
+		LastFailureAt: loginAttempt.LastFailureAt,
+	}
+}
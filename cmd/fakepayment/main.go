@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"ecommerce-api/database"
+	"ecommerce-api/payment"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	godotenv.Load()
+
+	reference := flag.String("reference", "", "fake payment intent reference, e.g. fake_3q2f7wEjR0Yg2vGkq8GdHc1b")
+	status := flag.String("status", payment.StatusSucceeded, "settle the intent as succeeded or failed")
+	flag.Parse()
+
+	if *reference == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	database := database.NewDatabase(os.Getenv("DATABASE_URL"))
+	defer database.CloseConn()
+
+	intent, err := payment.NewFakeProvider(database).Settle(context.Background(), *reference, *status)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("fake payment %s is %s, the user can now confirm the top-up", intent.Reference, intent.Status)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"ecommerce-api/oidc"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	godotenv.Load()
+
+	email := getEnv("MOCK_OIDC_EMAIL", "mock.user@example.com")
+	provider, err := oidc.NewMockProvider(
+		getEnv("OIDC_MOCK_CLIENT_ID", "ecommerce-api"),
+		os.Getenv("OIDC_MOCK_CLIENT_SECRET"),
+		oidc.MockUser{
+			Subject:       getEnv("MOCK_OIDC_SUBJECT", email),
+			Email:         email,
+			EmailVerified: true,
+			GivenName:     strings.SplitN(email, "@", 2)[0],
+			FamilyName:    "Mock",
+		},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	address := getEnv("MOCK_OIDC_ADDRESS", ":8081")
+	log.Printf("mock oidc provider listening on %s", address)
+	log.Fatal(http.ListenAndServe(address, provider))
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,11 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS refresh_token_reuse_events;
+
+DROP INDEX IF EXISTS refresh_tokens_expires_at_idx;
+DROP INDEX IF EXISTS refresh_tokens_family_id_idx;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS revoked_at,
+    DROP COLUMN IF EXISTS replaced_by,
+    DROP COLUMN IF EXISTS family_id;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- Add up migration script here
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    ADD COLUMN replaced_by UUID REFERENCES refresh_tokens(token) ON DELETE SET NULL,
+    ADD COLUMN revoked_at TIMESTAMP,
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens(family_id);
+CREATE INDEX refresh_tokens_expires_at_idx ON refresh_tokens(expires_at);
+
+CREATE TABLE refresh_token_reuse_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    token UUID NOT NULL,
+    family_id UUID NOT NULL,
+    user_email VARCHAR(255) NOT NULL REFERENCES users(email),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
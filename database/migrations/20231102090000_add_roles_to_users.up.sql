@@ -0,0 +1,5 @@
+-- Add up migration script here
+ALTER TABLE users ADD COLUMN roles VARCHAR(255)[] NOT NULL DEFAULT '{buyer}';
+
+UPDATE users SET roles = array_append(roles, 'seller')
+WHERE email IN (SELECT owner_email FROM stores);
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- Add down migration script here
+ALTER TABLE refresh_tokens
+    DROP CONSTRAINT IF EXISTS refresh_tokens_family_id_fkey,
+    ALTER COLUMN family_id SET DEFAULT uuid_generate_v4();
+
+DROP TABLE IF EXISTS sessions;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Add up migration script here
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_email VARCHAR(255) NOT NULL REFERENCES users(email),
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX sessions_user_email_idx ON sessions(user_email);
+
+INSERT INTO sessions (id, user_email, created_at, last_used_at)
+SELECT family_id, user_email, MIN(created_at), MAX(created_at)
+FROM refresh_tokens
+GROUP BY family_id, user_email;
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN family_id DROP DEFAULT,
+    ADD CONSTRAINT refresh_tokens_family_id_fkey FOREIGN KEY (family_id) REFERENCES sessions(id) ON DELETE CASCADE;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS password_reset_tokens;
\ No newline at end of file
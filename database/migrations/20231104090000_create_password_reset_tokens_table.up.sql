@@ -0,0 +1,10 @@
+-- Add up migration script here
+CREATE TABLE password_reset_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_email VARCHAR(255) NOT NULL REFERENCES users(email),
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP + INTERVAL '1 hour',
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX password_reset_tokens_user_email_idx ON password_reset_tokens(user_email);
\ No newline at end of file
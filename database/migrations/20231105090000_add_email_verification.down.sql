@@ -0,0 +1,4 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS email_verification_tokens;
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS two_factor_challenges;
+DROP TABLE IF EXISTS totp_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- Add up migration script here
+CREATE TABLE user_totp (
+    user_email VARCHAR(255) NOT NULL PRIMARY KEY REFERENCES users(email),
+    secret VARCHAR(64) NOT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    enabled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+SELECT sqlx_manage_updated_at('user_totp');
+
+CREATE TABLE totp_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_email VARCHAR(255) NOT NULL REFERENCES users(email),
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX totp_recovery_codes_user_email_idx ON totp_recovery_codes(user_email);
+
+CREATE TABLE two_factor_challenges (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_email VARCHAR(255) NOT NULL REFERENCES users(email),
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP + INTERVAL '5 minutes',
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
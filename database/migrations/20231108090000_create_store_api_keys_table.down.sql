@@ -0,0 +1,2 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS store_api_keys;
\ No newline at end of file
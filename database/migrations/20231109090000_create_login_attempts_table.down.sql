@@ -0,0 +1,3 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS login_lockouts;
+DROP TABLE IF EXISTS login_attempts;
\ No newline at end of file
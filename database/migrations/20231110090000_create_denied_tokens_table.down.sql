@@ -0,0 +1,3 @@
+-- Add down migration script here
+ALTER TABLE users DROP COLUMN IF EXISTS banned_at;
+DROP TABLE IF EXISTS denied_tokens;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- Add up migration script here
+CREATE TABLE denied_tokens (
+    key VARCHAR(320) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX denied_tokens_expires_at_idx ON denied_tokens(expires_at);
+
+ALTER TABLE users ADD COLUMN banned_at TIMESTAMP;
@@ -0,0 +1,7 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS wallet_ledger_entries;
+DROP FUNCTION IF EXISTS prevent_wallet_ledger_mutation();
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS refunded_at,
+    DROP COLUMN IF EXISTS amount;
\ No newline at end of file
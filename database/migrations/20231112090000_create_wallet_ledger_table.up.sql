@@ -0,0 +1,47 @@
+-- Add up migration script here
+ALTER TABLE transactions
+    ADD COLUMN amount BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN refunded_at TIMESTAMP;
+
+UPDATE transactions SET amount = transactions.quantity * products.price
+FROM products
+WHERE products.id = transactions.product_id;
+
+CREATE TABLE wallet_ledger_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_email VARCHAR(255) NOT NULL REFERENCES users(email) ON UPDATE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    amount BIGINT NOT NULL,
+    balance_after BIGINT NOT NULL CHECK (balance_after >= 0),
+    reference_id UUID,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX wallet_ledger_entries_user_email_idx ON wallet_ledger_entries(user_email, created_at);
+
+INSERT INTO wallet_ledger_entries (user_email, type, amount, balance_after, description)
+SELECT email, 'adjustment', balance, balance, 'Opening balance'
+FROM users
+WHERE balance <> 0;
+
+CREATE OR REPLACE FUNCTION prevent_wallet_ledger_mutation() RETURNS trigger AS $$
+BEGIN
+    IF (
+        TG_OP = 'DELETE' OR
+        NEW.id IS DISTINCT FROM OLD.id OR
+        NEW.type IS DISTINCT FROM OLD.type OR
+        NEW.amount IS DISTINCT FROM OLD.amount OR
+        NEW.balance_after IS DISTINCT FROM OLD.balance_after OR
+        NEW.reference_id IS DISTINCT FROM OLD.reference_id OR
+        NEW.description IS DISTINCT FROM OLD.description OR
+        NEW.created_at IS DISTINCT FROM OLD.created_at
+    ) THEN
+        RAISE EXCEPTION 'wallet ledger entries are immutable';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER prevent_wallet_ledger_mutation BEFORE UPDATE OR DELETE ON wallet_ledger_entries
+    FOR EACH ROW EXECUTE PROCEDURE prevent_wallet_ledger_mutation();
\ No newline at end of file
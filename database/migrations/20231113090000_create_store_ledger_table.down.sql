@@ -0,0 +1,6 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS store_opening_balances;
+DROP TABLE IF EXISTS store_ledger_entries;
+DROP FUNCTION IF EXISTS prevent_ledger_mutation();
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS commission;
\ No newline at end of file
@@ -0,0 +1,39 @@
+-- Add up migration script here
+ALTER TABLE transactions ADD COLUMN commission BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE store_ledger_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    store_id UUID NOT NULL REFERENCES stores(id),
+    type VARCHAR(32) NOT NULL,
+    gross_amount BIGINT NOT NULL,
+    commission BIGINT NOT NULL DEFAULT 0,
+    amount BIGINT NOT NULL,
+    reference_id UUID,
+    available_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX store_ledger_entries_store_id_idx ON store_ledger_entries(store_id, created_at);
+
+CREATE TABLE store_opening_balances (
+    store_id UUID PRIMARY KEY REFERENCES stores(id),
+    gross_amount BIGINT NOT NULL,
+    released_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO store_opening_balances (store_id, gross_amount)
+SELECT products.store_id, SUM(transactions.amount)
+FROM transactions
+JOIN products ON products.id = transactions.product_id
+WHERE transactions.refunded_at IS NULL
+GROUP BY products.store_id;
+
+CREATE OR REPLACE FUNCTION prevent_ledger_mutation() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION '% entries are immutable', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER prevent_store_ledger_mutation BEFORE UPDATE OR DELETE ON store_ledger_entries
+    FOR EACH ROW EXECUTE PROCEDURE prevent_ledger_mutation();
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS withdrawals;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- Add up migration script here
+CREATE TABLE withdrawals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    store_id UUID NOT NULL REFERENCES stores(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    status VARCHAR(16) NOT NULL DEFAULT 'requested',
+    rejection_reason VARCHAR(255),
+    payout_reference VARCHAR(255),
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX withdrawals_store_id_idx ON withdrawals(store_id, created_at);
+CREATE INDEX withdrawals_status_idx ON withdrawals(status);
+
+SELECT sqlx_manage_updated_at('withdrawals');
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- Add up migration script here
+CREATE TABLE transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sender_email VARCHAR(255) NOT NULL REFERENCES users(email) ON UPDATE CASCADE,
+    recipient_email VARCHAR(255) NOT NULL REFERENCES users(email) ON UPDATE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    idempotency_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (sender_email, idempotency_key)
+);
+
+CREATE INDEX transfers_sender_email_idx ON transfers(sender_email, created_at);
+CREATE INDEX transfers_recipient_email_idx ON transfers(recipient_email, created_at);
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS product_currency,
+    DROP COLUMN IF EXISTS product_amount,
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE transfers DROP COLUMN IF EXISTS currency;
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
+ALTER TABLE stores DROP COLUMN IF EXISTS currency;
+ALTER TABLE users DROP COLUMN IF EXISTS currency;
\ No newline at end of file
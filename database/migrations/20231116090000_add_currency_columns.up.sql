@@ -0,0 +1,28 @@
+-- Add up migration script here
+ALTER TABLE users ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE stores ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE products ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE transfers ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE transactions
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN product_amount BIGINT,
+    ADD COLUMN product_currency CHAR(3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN exchange_rate NUMERIC(24, 12) NOT NULL DEFAULT 1;
+
+UPDATE transactions SET product_amount = amount;
+
+ALTER TABLE transactions ALTER COLUMN product_amount SET NOT NULL;
+
+CREATE TABLE exchange_rates (
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate NUMERIC(24, 12) NOT NULL CHECK (rate > 0),
+    updated_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base_currency, quote_currency),
+    CHECK (base_currency <> quote_currency)
+);
+
+SELECT sqlx_manage_updated_at('exchange_rates');
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Add down migration script here
+ALTER TABLE transactions DROP COLUMN IF EXISTS shipping_address;
+
+DROP TABLE IF EXISTS addresses;
\ No newline at end of file
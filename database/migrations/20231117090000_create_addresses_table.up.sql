@@ -0,0 +1,23 @@
+-- Add up migration script here
+CREATE TABLE addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_email VARCHAR(255) NOT NULL REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE,
+    type VARCHAR(16) NOT NULL,
+    recipient_name VARCHAR(255) NOT NULL,
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255) NOT NULL DEFAULT '',
+    city VARCHAR(255) NOT NULL,
+    region VARCHAR(255) NOT NULL DEFAULT '',
+    postal_code VARCHAR(16) NOT NULL,
+    country CHAR(2) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX addresses_user_email_idx ON addresses(user_email);
+CREATE UNIQUE INDEX addresses_default_idx ON addresses(user_email, type) WHERE is_default;
+
+SELECT sqlx_manage_updated_at('addresses');
+
+ALTER TABLE transactions ADD COLUMN shipping_address JSONB;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Add up migration script here
+ALTER TABLE users ADD COLUMN avatar JSONB;
\ No newline at end of file
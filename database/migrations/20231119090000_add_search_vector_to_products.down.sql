@@ -0,0 +1,4 @@
+-- Add down migration script here
+DROP INDEX IF EXISTS products_search_vector_idx;
+
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
\ No newline at end of file
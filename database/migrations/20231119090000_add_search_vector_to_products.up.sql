@@ -0,0 +1,7 @@
+-- Add up migration script here
+ALTER TABLE products ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+) STORED;
+
+CREATE INDEX products_search_vector_idx ON products USING GIN (search_vector);
\ No newline at end of file
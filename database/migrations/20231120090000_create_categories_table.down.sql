@@ -0,0 +1,8 @@
+-- Add down migration script here
+DROP INDEX IF EXISTS products_tags_idx;
+DROP INDEX IF EXISTS products_category_id_idx;
+
+ALTER TABLE products DROP COLUMN IF EXISTS tags;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS categories;
\ No newline at end of file
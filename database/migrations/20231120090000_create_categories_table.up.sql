@@ -0,0 +1,19 @@
+-- Add up migration script here
+CREATE TABLE categories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX categories_parent_id_idx ON categories(parent_id);
+CREATE UNIQUE INDEX categories_parent_name_idx ON categories(COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), LOWER(name));
+
+SELECT sqlx_manage_updated_at('categories');
+
+ALTER TABLE products ADD COLUMN category_id UUID REFERENCES categories(id) ON DELETE SET NULL;
+ALTER TABLE products ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX products_category_id_idx ON products(category_id);
+CREATE INDEX products_tags_idx ON products USING GIN (tags);
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Add down migration script here
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_normalized;
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- Add up migration script here
+DO $$
+DECLARE
+    duplicate RECORD;
+    renamed_email VARCHAR(255);
+BEGIN
+    FOR duplicate IN
+        SELECT email, normalized_email
+        FROM (
+            SELECT email, LOWER(TRIM(email)) AS normalized_email, ROW_NUMBER() OVER (
+                PARTITION BY LOWER(TRIM(email))
+                ORDER BY email = LOWER(TRIM(email)) DESC, created_at, email
+            ) AS position
+            FROM users
+        ) ranked
+        WHERE position > 1
+    LOOP
+        renamed_email := 'duplicate-' || MD5(duplicate.email) || '@invalid';
+        RAISE WARNING 'user % collides with % after email normalization and was renamed to %, merge or restore it by hand', duplicate.email, duplicate.normalized_email, renamed_email;
+        UPDATE users SET email = renamed_email WHERE email = duplicate.email;
+    END LOOP;
+END $$;
+
+UPDATE users SET email = LOWER(TRIM(email))
+WHERE email <> LOWER(TRIM(email));
+
+ALTER TABLE users ADD CONSTRAINT users_email_normalized CHECK (email = LOWER(TRIM(email)));
\ No newline at end of file
@@ -0,0 +1,40 @@
+-- Add down migration script here
+ALTER TABLE refresh_tokens
+    DROP CONSTRAINT refresh_tokens_user_email_fkey,
+    ADD CONSTRAINT refresh_tokens_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE refresh_token_reuse_events
+    DROP CONSTRAINT refresh_token_reuse_events_user_email_fkey,
+    ADD CONSTRAINT refresh_token_reuse_events_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE sessions
+    DROP CONSTRAINT sessions_user_email_fkey,
+    ADD CONSTRAINT sessions_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE password_reset_tokens
+    DROP CONSTRAINT password_reset_tokens_user_email_fkey,
+    ADD CONSTRAINT password_reset_tokens_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE email_verification_tokens
+    DROP CONSTRAINT email_verification_tokens_user_email_fkey,
+    ADD CONSTRAINT email_verification_tokens_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE user_totp
+    DROP CONSTRAINT user_totp_user_email_fkey,
+    ADD CONSTRAINT user_totp_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE totp_recovery_codes
+    DROP CONSTRAINT totp_recovery_codes_user_email_fkey,
+    ADD CONSTRAINT totp_recovery_codes_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE two_factor_challenges
+    DROP CONSTRAINT two_factor_challenges_user_email_fkey,
+    ADD CONSTRAINT two_factor_challenges_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE user_identities
+    DROP CONSTRAINT user_identities_user_email_fkey,
+    ADD CONSTRAINT user_identities_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE addresses
+    DROP CONSTRAINT addresses_user_email_fkey,
+    ADD CONSTRAINT addresses_user_email_fkey FOREIGN KEY (user_email) REFERENCES users(email) ON UPDATE CASCADE ON DELETE CASCADE;
\ No newline at end of file
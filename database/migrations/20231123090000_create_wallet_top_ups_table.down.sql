@@ -0,0 +1,2 @@
+-- Add down migration script here
+DROP TABLE IF EXISTS wallet_top_ups;
\ No newline at end of file
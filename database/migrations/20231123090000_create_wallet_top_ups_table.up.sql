@@ -0,0 +1,18 @@
+-- Add up migration script here
+CREATE TABLE wallet_top_ups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_email VARCHAR(255) NOT NULL REFERENCES users(email) ON UPDATE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    provider_reference VARCHAR(255),
+    checkout_url TEXT,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX wallet_top_ups_user_email_idx ON wallet_top_ups(user_email, created_at);
+CREATE UNIQUE INDEX wallet_top_ups_provider_reference_idx ON wallet_top_ups(provider_reference);
+
+SELECT sqlx_manage_updated_at('wallet_top_ups');
\ No newline at end of file
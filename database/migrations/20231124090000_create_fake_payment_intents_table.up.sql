@@ -0,0 +1,13 @@
+-- Add up migration script here
+CREATE TABLE fake_payment_intents (
+    reference VARCHAR(255) PRIMARY KEY,
+    top_up_id UUID NOT NULL UNIQUE,
+    user_email VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+SELECT sqlx_manage_updated_at('fake_payment_intents');
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Add down migration script here
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_quantity_positive;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Add up migration script here
+ALTER TABLE transactions ADD CONSTRAINT transactions_quantity_positive CHECK (quantity > 0) NOT VALID;
\ No newline at end of file
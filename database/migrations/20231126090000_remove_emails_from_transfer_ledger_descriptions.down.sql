@@ -0,0 +1 @@
+-- Add down migration script here
\ No newline at end of file
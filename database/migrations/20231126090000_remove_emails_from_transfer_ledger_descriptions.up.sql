@@ -0,0 +1,10 @@
+-- Add up migration script here
+ALTER TABLE wallet_ledger_entries DISABLE TRIGGER prevent_wallet_ledger_mutation;
+
+UPDATE wallet_ledger_entries SET description = 'Transfer sent'
+WHERE type = 'transfer_out' AND description <> 'Transfer sent';
+
+UPDATE wallet_ledger_entries SET description = 'Transfer received'
+WHERE type = 'transfer_in' AND description <> 'Transfer received';
+
+ALTER TABLE wallet_ledger_entries ENABLE TRIGGER prevent_wallet_ledger_mutation;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- Add down migration script here
+ALTER TABLE transactions
+    ALTER COLUMN exchange_rate DROP DEFAULT,
+    ALTER COLUMN exchange_rate TYPE NUMERIC(24, 12) USING (SPLIT_PART(exchange_rate, '/', 1)::NUMERIC / COALESCE(NULLIF(SPLIT_PART(exchange_rate, '/', 2), ''), '1')::NUMERIC),
+    ALTER COLUMN exchange_rate SET DEFAULT 1;
+
+ALTER TABLE exchange_rates ALTER COLUMN rate TYPE NUMERIC(24, 12);
\ No newline at end of file
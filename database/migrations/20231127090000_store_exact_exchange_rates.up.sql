@@ -0,0 +1,7 @@
+-- Add up migration script here
+ALTER TABLE exchange_rates ALTER COLUMN rate TYPE NUMERIC;
+
+ALTER TABLE transactions
+    ALTER COLUMN exchange_rate DROP DEFAULT,
+    ALTER COLUMN exchange_rate TYPE VARCHAR(255) USING TRIM(TRAILING '.' FROM TRIM(TRAILING '0' FROM exchange_rate::TEXT)),
+    ALTER COLUMN exchange_rate SET DEFAULT '1';
\ No newline at end of file
@@ -0,0 +1,82 @@
+package denylist
+
+import (
+	"time"
+)
+
+type Store interface {
+	Add(key string, expiresAt time.Time) error
+	Contains(keys []string, now time.Time) (bool, error)
+	Purge(now time.Time) error
+}
+
+type Denylist struct {
+	store         Store
+	tokenLifetime time.Duration
+	now           func() time.Time
+}
+
+func NewDenylist(store Store, tokenLifetime time.Duration) *Denylist {
+	return &Denylist{
+		store:         store,
+		tokenLifetime: tokenLifetime,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+func (d *Denylist) RevokeToken(tokenID string) error {
+	if tokenID == "" {
+		return nil
+	}
+
+	return d.add(tokenKey(tokenID))
+}
+
+func (d *Denylist) RevokeSessions(sessionIDs ...string) error {
+	for _, sessionID := range sessionIDs {
+		if sessionID == "" {
+			continue
+		}
+
+		if err := d.add(sessionKey(sessionID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Denylist) IsRevoked(tokenID string, sessionID string) (bool, error) {
+	var keys []string
+	if tokenID != "" {
+		keys = append(keys, tokenKey(tokenID))
+	}
+	if sessionID != "" {
+		keys = append(keys, sessionKey(sessionID))
+	}
+
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	return d.store.Contains(keys, d.now())
+}
+
+func (d *Denylist) add(key string) error {
+	now := d.now()
+	if err := d.store.Purge(now); err != nil {
+		return err
+	}
+
+	return d.store.Add(key, now.Add(d.tokenLifetime))
+}
+
+func tokenKey(tokenID string) string {
+	return "jti:" + tokenID
+}
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
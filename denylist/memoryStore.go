@@ -0,0 +1,54 @@
+package denylist
+
+import (
+	"sync"
+	"time"
+)
+
+type MemoryStore struct {
+	mutex   sync.RWMutex
+	entries map[string]time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: map[string]time.Time{},
+	}
+}
+
+func (s *MemoryStore) Add(key string, expiresAt time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if current, ok := s.entries[key]; !ok || current.Before(expiresAt) {
+		s.entries[key] = expiresAt
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Contains(keys []string, now time.Time) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, key := range keys {
+		if expiresAt, ok := s.entries[key]; ok && expiresAt.After(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *MemoryStore) Purge(now time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, expiresAt := range s.entries {
+		if !expiresAt.After(now) {
+			delete(s.entries, key)
+		}
+	}
+
+	return nil
+}
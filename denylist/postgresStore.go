@@ -0,0 +1,34 @@
+package denylist
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/model"
+	"time"
+)
+
+type PostgresStore struct {
+	database *database.Database
+}
+
+func NewPostgresStore(database *database.Database) *PostgresStore {
+	return &PostgresStore{
+		database: database,
+	}
+}
+
+func (s *PostgresStore) Add(key string, expiresAt time.Time) error {
+	deniedToken := model.DeniedToken{
+		Key:       key,
+		ExpiresAt: &expiresAt,
+	}
+
+	return deniedToken.Create(s.database.Conn)
+}
+
+func (s *PostgresStore) Contains(keys []string, now time.Time) (bool, error) {
+	return model.ExistsDeniedToken(s.database.Conn, keys, now)
+}
+
+func (s *PostgresStore) Purge(now time.Time) error {
+	return model.DeleteExpiredDeniedTokens(s.database.Conn, now)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type AddressHandler struct {
+	database       *database.Database
+	validator      *validator.Validate
+	addressService *service.AddressService
+}
+
+func NewAddressHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	addressService *service.AddressService,
+) *AddressHandler {
+	return &AddressHandler{
+		database:       database,
+		validator:      validator,
+		addressService: addressService,
+	}
+}
+
+func (h *AddressHandler) GetAllCurrentUserAddress(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	addresses, err := h.addressService.GetAll(principal.Email)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, addresses)
+}
+
+func (h *AddressHandler) GetCurrentUserAddress(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	addressID := c.Param("id")
+	if err := h.validator.Var(addressID, "uuid"); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Address not found")
+	}
+
+	address, err := h.addressService.Get(principal.Email, addressID)
+	switch err {
+	case service.ErrAddressNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Address not found")
+	case nil:
+		return c.JSON(http.StatusOK, address)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *AddressHandler) CreateCurrentUserAddress(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	saveRequest, err := h.bindAddressSave(c)
+	if err != nil {
+		return err
+	}
+
+	address, err := h.addressService.Create(principal.Email, saveRequest)
+	switch err {
+	case helper.ErrInvalidPostalCode:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case nil:
+		return c.JSON(http.StatusCreated, address)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *AddressHandler) UpdateCurrentUserAddress(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	addressID := c.Param("id")
+	if err := h.validator.Var(addressID, "uuid"); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Address not found")
+	}
+
+	saveRequest, err := h.bindAddressSave(c)
+	if err != nil {
+		return err
+	}
+
+	address, err := h.addressService.Update(principal.Email, addressID, saveRequest)
+	switch err {
+	case service.ErrAddressNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Address not found")
+	case helper.ErrInvalidPostalCode:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case nil:
+		return c.JSON(http.StatusOK, address)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *AddressHandler) DeleteCurrentUserAddress(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	addressID := c.Param("id")
+	if err := h.validator.Var(addressID, "uuid"); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Address not found")
+	}
+
+	err = h.addressService.Delete(principal.Email, addressID)
+	switch err {
+	case service.ErrAddressNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Address not found")
+	case nil:
+		return c.NoContent(http.StatusOK)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *AddressHandler) bindAddressSave(c echo.Context) (model.AddressSave, error) {
+	saveRequest := model.AddressSave{
+		Type:          c.FormValue("type"),
+		RecipientName: c.FormValue("recipient_name"),
+		Line1:         c.FormValue("line1"),
+		Line2:         c.FormValue("line2"),
+		City:          c.FormValue("city"),
+		Region:        c.FormValue("region"),
+		PostalCode:    c.FormValue("postal_code"),
+		Country:       strings.ToUpper(c.FormValue("country")),
+	}
+
+	if isDefault := c.FormValue("is_default"); isDefault != "" {
+		parsed, err := strconv.ParseBool(isDefault)
+		if err != nil {
+			return saveRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid is_default")
+		}
+		saveRequest.IsDefault = parsed
+	}
+
+	if err := h.validator.Struct(saveRequest); err != nil {
+		return saveRequest, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return saveRequest, nil
+}
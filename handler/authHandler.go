@@ -1,40 +1,47 @@
 package handler
 
 import (
+	"ecommerce-api/bruteforce"
 	"ecommerce-api/database"
 	"ecommerce-api/helper"
 	"ecommerce-api/model"
 	"ecommerce-api/service"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 )
 
 type AuthHandler struct {
-	database    *database.Database
-	validator   *validator.Validate
-	authService *service.AuthService
-	jwtKey      []byte
+	database       *database.Database
+	validator      *validator.Validate
+	passwordPolicy helper.PasswordPolicy
+	authService    *service.AuthService
+	userService    *service.UserService
 }
 
 func NewAuthHandler(
 	database *database.Database,
 	validator *validator.Validate,
+	passwordPolicy helper.PasswordPolicy,
 	authService *service.AuthService,
-	jwtKey []byte,
+	userService *service.UserService,
 ) *AuthHandler {
 	return &AuthHandler{
-		database:    database,
-		validator:   validator,
-		authService: authService,
-		jwtKey:      jwtKey,
+		database:       database,
+		validator:      validator,
+		passwordPolicy: passwordPolicy,
+		authService:    authService,
+		userService:    userService,
 	}
 }
 
 func (h *AuthHandler) Login(c echo.Context) error {
 	loginRequest := model.UserLogin{
-		Email:    c.FormValue("email"),
+		Email:    helper.NormalizeEmail(c.FormValue("email")),
 		Password: c.FormValue("password"),
 	}
 
@@ -42,15 +49,51 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	accessToken, err := h.authService.Login(loginRequest, c)
+	result, err := h.authService.Login(loginRequest, c)
+
+	var lockedErr *bruteforce.LockedError
+	if errors.As(err, &lockedErr) {
+		return tooManyAttempts(c, lockedErr)
+	}
 
 	switch err {
 	case service.ErrInvalidEmailOrPassword:
 		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid email or password")
+	case service.ErrUserBanned:
+		return echo.NewHTTPError(http.StatusForbidden, "Your account has been banned")
 	case nil:
-		return c.JSON(http.StatusOK, echo.Map{
-			"access_token": accessToken,
-		})
+		return c.JSON(http.StatusOK, result)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *AuthHandler) VerifyTwoFactor(c echo.Context) error {
+	verifyRequest := model.TwoFactorVerify{
+		ChallengeToken: c.FormValue("challenge_token"),
+		Code:           c.FormValue("code"),
+	}
+
+	if err := h.validator.Struct(verifyRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.authService.VerifyTwoFactor(verifyRequest, c)
+
+	var lockedErr *bruteforce.LockedError
+	if errors.As(err, &lockedErr) {
+		return tooManyAttempts(c, lockedErr)
+	}
+
+	switch err {
+	case service.ErrInvalidTwoFactorChallenge:
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired two-factor challenge")
+	case service.ErrInvalidTwoFactorCode, service.ErrTwoFactorNotEnabled:
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid two-factor code")
+	case service.ErrUserBanned:
+		return echo.NewHTTPError(http.StatusForbidden, "Your account has been banned")
+	case nil:
+		return c.JSON(http.StatusOK, result)
 	default:
 		return echo.ErrInternalServerError
 	}
@@ -62,39 +105,100 @@ func (h *AuthHandler) Refresh(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid refresh token")
 	}
 
-	refreshToken := model.RefreshToken{
-		Token: cookie.Value,
+	if err := h.validator.Var(cookie.Value, "uuid"); err != nil {
+		helper.ClearRefreshTokenCookies(c)
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid refresh token")
 	}
 
-	if err := refreshToken.GetByToken(h.database.Conn); err != nil {
+	accessToken, err := h.authService.Refresh(cookie.Value, c)
+	switch err {
+	case service.ErrInvalidRefreshToken, service.ErrRefreshTokenReused:
+		helper.ClearRefreshTokenCookies(c)
 		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid refresh token")
+	case service.ErrRefreshTokenExpired:
+		helper.ClearRefreshTokenCookies(c)
+		return echo.NewHTTPError(http.StatusUnauthorized, "Refresh token expired")
+	case service.ErrUserBanned:
+		helper.ClearRefreshTokenCookies(c)
+		return echo.NewHTTPError(http.StatusForbidden, "Your account has been banned")
+	case nil:
+		return c.JSON(http.StatusOK, echo.Map{
+			"access_token": accessToken,
+		})
+	default:
+		return echo.ErrInternalServerError
 	}
+}
 
-	signedToken, err := helper.GenerateJwtToken(refreshToken.UserEmail, h.jwtKey)
+func (h *AuthHandler) Logout(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
 	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	var refreshToken string
+	if cookie, err := c.Cookie("refresh_token"); err == nil {
+		refreshToken = cookie.Value
+	}
+
+	switch err := h.authService.Logout(refreshToken, principal); err {
+	case service.ErrInvalidRefreshToken:
+		helper.ClearRefreshTokenCookies(c)
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid refresh token")
+	case nil:
+		helper.ClearRefreshTokenCookies(c)
+		return c.NoContent(http.StatusOK)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *AuthHandler) ForgotPassword(c echo.Context) error {
+	forgotRequest := model.PasswordForgot{
+		Email: helper.NormalizeEmail(c.FormValue("email")),
+	}
+
+	if err := h.validator.Struct(forgotRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.userService.ForgotPassword(forgotRequest); err != nil {
 		return echo.ErrInternalServerError
 	}
 
 	return c.JSON(http.StatusOK, echo.Map{
-		"access_token": signedToken,
+		"message": "If the email is registered, a password reset link has been sent",
 	})
 }
 
-func (h *AuthHandler) Logout(c echo.Context) error {
-	cookie, err := c.Cookie("refresh_token")
-	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid refresh token")
+func (h *AuthHandler) ResetPassword(c echo.Context) error {
+	resetRequest := model.PasswordReset{
+		Token:    c.FormValue("token"),
+		Password: c.FormValue("password"),
 	}
 
-	refreshToken := model.RefreshToken{
-		Token: cookie.Value,
+	if err := h.validator.Struct(resetRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.passwordPolicy.Validate(resetRequest.Password); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if err := refreshToken.Delete(h.database.Conn); err != nil {
+	switch err := h.userService.ResetPassword(resetRequest); err {
+	case service.ErrInvalidResetToken:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired reset token")
+	case nil:
+		helper.ClearRefreshTokenCookies(c)
+		return c.NoContent(http.StatusOK)
+	default:
 		return echo.ErrInternalServerError
 	}
+}
 
-	helper.ClearRefreshTokenCookies(c)
+func tooManyAttempts(c echo.Context, lockedErr *bruteforce.LockedError) error {
+	retryAfter := int(math.Ceil(lockedErr.RetryAfter.Seconds()))
+	c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
 
-	return c.NoContent(http.StatusOK)
+	return echo.NewHTTPError(http.StatusTooManyRequests, "Too many failed login attempts, try again later")
 }
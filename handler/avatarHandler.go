@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/service"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type AvatarHandler struct {
+	database      *database.Database
+	validator     *validator.Validate
+	avatarService *service.AvatarService
+}
+
+func NewAvatarHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	avatarService *service.AvatarService,
+) *AvatarHandler {
+	return &AvatarHandler{
+		database:      database,
+		validator:     validator,
+		avatarService: avatarService,
+	}
+}
+
+func (h *AvatarHandler) UploadCurrent(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Avatar file is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+	defer file.Close()
+
+	user, err := h.avatarService.Upload(c.Request().Context(), principal.Email, file, fileHeader.Header.Get("Content-Type"))
+	switch err {
+	case service.ErrAvatarTooLarge:
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Avatar is too large")
+	case service.ErrUnsupportedAvatarType:
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "Avatar must be a JPEG, PNG or GIF image")
+	case service.ErrInvalidAvatar:
+		return echo.NewHTTPError(http.StatusBadRequest, "Avatar could not be read as an image")
+	case nil:
+		return c.JSON(http.StatusOK, serializeUser(c, user))
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *AvatarHandler) DeleteCurrent(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	user, err := h.avatarService.Delete(c.Request().Context(), principal.Email)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, serializeUser(c, user))
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/model"
+	"ecommerce-api/service"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type CategoryHandler struct {
+	database        *database.Database
+	validator       *validator.Validate
+	categoryService *service.CategoryService
+}
+
+func NewCategoryHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	categoryService *service.CategoryService,
+) *CategoryHandler {
+	return &CategoryHandler{
+		database:        database,
+		validator:       validator,
+		categoryService: categoryService,
+	}
+}
+
+func (h *CategoryHandler) GetTree(c echo.Context) error {
+	categories, err := h.categoryService.GetTree()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, categories)
+}
+
+func (h *CategoryHandler) GetByID(c echo.Context) error {
+	id, err := h.categoryID(c)
+	if err != nil {
+		return err
+	}
+
+	category, err := h.categoryService.Get(id)
+	switch err {
+	case service.ErrCategoryNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Category not found")
+	case nil:
+		return c.JSON(http.StatusOK, category)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *CategoryHandler) GetAllProduct(c echo.Context) error {
+	id, err := h.categoryID(c)
+	if err != nil {
+		return err
+	}
+
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	filter := model.ProductFilter{
+		CategoryID: id,
+		Currency:   strings.ToUpper(c.QueryParam("currency")),
+		Tag:        strings.ToLower(strings.TrimSpace(c.QueryParam("tag"))),
+	}
+	if filter.InStock, err = queryBool(c, "in_stock"); err != nil {
+		return err
+	}
+	if err := requirePriceCurrency(filter, query); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if _, err := h.categoryService.Get(id); err != nil {
+		if err == service.ErrCategoryNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, "Category not found")
+		}
+		return echo.ErrInternalServerError
+	}
+
+	products, err := model.GetAllProduct(h.database.Conn, filter, query)
+	if err != nil {
+		return listQueryError(err)
+	}
+
+	return c.JSON(http.StatusOK, products)
+}
+
+func (h *CategoryHandler) Create(c echo.Context) error {
+	saveRequest, err := h.bindCategorySave(c)
+	if err != nil {
+		return err
+	}
+
+	category, err := h.categoryService.Create(saveRequest)
+	switch err {
+	case service.ErrParentCategoryNotFound:
+		return echo.NewHTTPError(http.StatusBadRequest, "Parent category not found")
+	case service.ErrCategoryNameTaken:
+		return echo.NewHTTPError(http.StatusConflict, "Category name already taken")
+	case nil:
+		return c.JSON(http.StatusCreated, category)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *CategoryHandler) Update(c echo.Context) error {
+	id, err := h.categoryID(c)
+	if err != nil {
+		return err
+	}
+
+	saveRequest, err := h.bindCategorySave(c)
+	if err != nil {
+		return err
+	}
+
+	category, err := h.categoryService.Update(id, saveRequest)
+	switch err {
+	case service.ErrCategoryNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Category not found")
+	case service.ErrParentCategoryNotFound:
+		return echo.NewHTTPError(http.StatusBadRequest, "Parent category not found")
+	case service.ErrCategoryCycle:
+		return echo.NewHTTPError(http.StatusBadRequest, "Category cannot be moved under itself")
+	case service.ErrCategoryNameTaken:
+		return echo.NewHTTPError(http.StatusConflict, "Category name already taken")
+	case nil:
+		return c.JSON(http.StatusOK, category)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *CategoryHandler) Delete(c echo.Context) error {
+	id, err := h.categoryID(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.categoryService.Delete(id)
+	switch err {
+	case service.ErrCategoryNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Category not found")
+	case service.ErrCategoryHasChildren:
+		return echo.NewHTTPError(http.StatusConflict, "Category has subcategories")
+	case nil:
+		return c.NoContent(http.StatusOK)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *CategoryHandler) categoryID(c echo.Context) (string, error) {
+	id := c.Param("id")
+	if err := h.validator.Var(id, "uuid"); err != nil {
+		return id, echo.NewHTTPError(http.StatusNotFound, "Category not found")
+	}
+
+	return id, nil
+}
+
+func (h *CategoryHandler) bindCategorySave(c echo.Context) (model.CategorySave, error) {
+	saveRequest := model.CategorySave{
+		Name:     strings.TrimSpace(c.FormValue("name")),
+		ParentID: c.FormValue("parent_id"),
+	}
+
+	if err := h.validator.Struct(saveRequest); err != nil {
+		return saveRequest, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return saveRequest, nil
+}
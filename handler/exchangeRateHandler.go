@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/money"
+	"ecommerce-api/service"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type ExchangeRateHandler struct {
+	database            *database.Database
+	validator           *validator.Validate
+	exchangeRateService *service.ExchangeRateService
+}
+
+func NewExchangeRateHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	exchangeRateService *service.ExchangeRateService,
+) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		database:            database,
+		validator:           validator,
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+func (h *ExchangeRateHandler) GetAll(c echo.Context) error {
+	rates, err := h.exchangeRateService.GetAll()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, rates)
+}
+
+func (h *ExchangeRateHandler) Set(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	updateRequest := model.ExchangeRateUpdate{
+		BaseCurrency:  strings.ToUpper(c.Param("base")),
+		QuoteCurrency: strings.ToUpper(c.Param("quote")),
+		Rate:          c.FormValue("rate"),
+	}
+
+	if err := h.validator.Struct(updateRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	rate, err := h.exchangeRateService.Set(updateRequest, principal.Email)
+	switch err {
+	case money.ErrInvalidRate:
+		return echo.NewHTTPError(http.StatusBadRequest, "Rate must be a positive number")
+	case nil:
+		return c.JSON(http.StatusOK, rate)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *ExchangeRateHandler) Delete(c echo.Context) error {
+	err := h.exchangeRateService.Delete(strings.ToUpper(c.Param("base")), strings.ToUpper(c.Param("quote")))
+	switch err {
+	case service.ErrExchangeRateNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Exchange rate not found")
+	case nil:
+		return c.NoContent(http.StatusOK)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"ecommerce-api/helper"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type JwksHandler struct {
+	jwtKeySet *helper.JwtKeySet
+}
+
+func NewJwksHandler(jwtKeySet *helper.JwtKeySet) *JwksHandler {
+	return &JwksHandler{
+		jwtKeySet: jwtKeySet,
+	}
+}
+
+func (h *JwksHandler) Get(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+
+	return c.JSON(http.StatusOK, h.jwtKeySet.Jwks())
+}
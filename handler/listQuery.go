@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"ecommerce-api/model"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func bindListQuery(c echo.Context) (model.ListQuery, error) {
+	query := model.ListQuery{
+		Sort:   c.QueryParam("sort"),
+		Cursor: c.QueryParam("cursor"),
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 {
+			return query, echo.NewHTTPError(http.StatusBadRequest, "Invalid limit")
+		}
+		query.Limit = parsed
+	}
+
+	return query, nil
+}
+
+func queryInt64(c echo.Context, name string) (*int64, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid "+name)
+	}
+
+	return &parsed, nil
+}
+
+func queryBool(c echo.Context, name string) (*bool, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid "+name)
+	}
+
+	return &parsed, nil
+}
+
+func listQueryError(err error) error {
+	switch err {
+	case model.ErrInvalidSort:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid sort")
+	case model.ErrInvalidCursor:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid cursor")
+	default:
+		return echo.ErrInternalServerError
+	}
+}
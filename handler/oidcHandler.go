@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/model"
+	"ecommerce-api/service"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type OidcHandler struct {
+	database    *database.Database
+	validator   *validator.Validate
+	oidcService *service.OidcService
+}
+
+func NewOidcHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	oidcService *service.OidcService,
+) *OidcHandler {
+	return &OidcHandler{
+		database:    database,
+		validator:   validator,
+		oidcService: oidcService,
+	}
+}
+
+func (h *OidcHandler) Login(c echo.Context) error {
+	authUrl, err := h.oidcService.Begin(c.Request().Context(), c.Param("provider"), c)
+	switch err {
+	case service.ErrOidcProviderNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Provider not found")
+	case nil:
+		return c.Redirect(http.StatusFound, authUrl)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *OidcHandler) Callback(c echo.Context) error {
+	if c.QueryParam("error") != "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Login was cancelled or denied by the provider")
+	}
+
+	callbackRequest := model.OidcCallback{
+		State: c.QueryParam("state"),
+		Code:  c.QueryParam("code"),
+	}
+
+	if err := h.validator.Struct(callbackRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.oidcService.Callback(c.Param("provider"), callbackRequest, c)
+	switch err {
+	case service.ErrOidcProviderNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Provider not found")
+	case service.ErrInvalidOidcState:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired login state")
+	case service.ErrOidcLoginFailed:
+		return echo.NewHTTPError(http.StatusUnauthorized, "Login with provider failed")
+	case service.ErrOidcEmailNotVerified:
+		return echo.NewHTTPError(http.StatusForbidden, "Provider did not return a verified email address")
+	case service.ErrUserBanned:
+		return echo.NewHTTPError(http.StatusForbidden, "Your account has been banned")
+	case nil:
+		return c.JSON(http.StatusOK, result)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
@@ -2,11 +2,13 @@ package handler
 
 import (
 	"ecommerce-api/database"
+	"ecommerce-api/helper"
 	"ecommerce-api/model"
 	"ecommerce-api/service"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -31,14 +33,70 @@ func NewProductHandler(
 }
 
 func (h *ProductHandler) GetAll(c echo.Context) error {
-	products, err := model.GetAllProduct(h.database.Conn)
+	query, err := bindListQuery(c)
 	if err != nil {
-		return echo.ErrInternalServerError
+		return err
+	}
+
+	filter := model.ProductFilter{
+		StoreID:    c.QueryParam("store_id"),
+		Currency:   strings.ToUpper(c.QueryParam("currency")),
+		CategoryID: c.QueryParam("category_id"),
+		Tag:        strings.ToLower(strings.TrimSpace(c.QueryParam("tag"))),
+	}
+	if filter.MinPrice, err = queryInt64(c, "min_price"); err != nil {
+		return err
+	}
+	if filter.MaxPrice, err = queryInt64(c, "max_price"); err != nil {
+		return err
+	}
+	if filter.InStock, err = queryBool(c, "in_stock"); err != nil {
+		return err
+	}
+	if err := requirePriceCurrency(filter, query); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	products, err := model.GetAllProduct(h.database.Conn, filter, query)
+	if err != nil {
+		return listQueryError(err)
 	}
 
 	return c.JSON(http.StatusOK, products)
 }
 
+func (h *ProductHandler) Search(c echo.Context) error {
+	if c.QueryParams().Has("cursor") || c.QueryParams().Has("sort") {
+		return echo.NewHTTPError(http.StatusBadRequest, "Search results are ordered by rank and don't support sort or cursor")
+	}
+
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	search := model.ProductSearch{
+		Query: strings.TrimSpace(c.QueryParam("q")),
+		Limit: query.Limit,
+	}
+
+	if err := h.validator.Struct(search); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	results, err := model.SearchProduct(h.database.Conn, search)
+	if err != nil {
+		log.Println(err)
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
+
 func (h *ProductHandler) GetByID(c echo.Context) error {
 	product := model.Product{
 		ID: c.Param("id"),
@@ -51,7 +109,29 @@ func (h *ProductHandler) GetByID(c echo.Context) error {
 	return c.JSON(http.StatusOK, product)
 }
 
+func (h *ProductHandler) GetAllCurrentStoreProduct(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	products, err := h.productService.GetAllCurrentStoreProduct(principal)
+	switch err {
+	case service.ErrDontHaveStore:
+		return echo.NewHTTPError(http.StatusBadRequest, "You don't have a store yet")
+	case nil:
+		return c.JSON(http.StatusOK, products)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
 func (h *ProductHandler) CreateCurrentStoreProduct(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
 	price, err := strconv.ParseInt(c.FormValue("price"), 10, 64)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid price")
@@ -67,16 +147,20 @@ func (h *ProductHandler) CreateCurrentStoreProduct(c echo.Context) error {
 		Description: c.FormValue("description"),
 		Price:       price,
 		Stock:       int(stock),
+		CategoryID:  c.FormValue("category_id"),
+		Tags:        helper.ParseTags(c.FormValue("tags")),
 	}
 
 	if err := h.validator.Struct(createRequest); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	product, err := h.productService.Create(createRequest, c)
+	product, err := h.productService.Create(createRequest, principal)
 	switch err {
 	case service.ErrDontHaveStore:
 		return echo.NewHTTPError(http.StatusBadRequest, "You don't have a store yet")
+	case service.ErrCategoryNotFound:
+		return echo.NewHTTPError(http.StatusBadRequest, "Category not found")
 	case nil:
 		return c.JSON(http.StatusCreated, product)
 	default:
@@ -85,6 +169,11 @@ func (h *ProductHandler) CreateCurrentStoreProduct(c echo.Context) error {
 }
 
 func (h *ProductHandler) UpdateCurrentStoreProduct(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
 	price, err := strconv.ParseInt(c.FormValue("price"), 10, 64)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid price")
@@ -103,16 +192,27 @@ func (h *ProductHandler) UpdateCurrentStoreProduct(c echo.Context) error {
 		Stock:       int(stock),
 	}
 
+	if categoryID, ok := formValueIfPresent(c, "category_id"); ok {
+		updateRequest.CategoryID = &categoryID
+	}
+
+	if tags, ok := formValueIfPresent(c, "tags"); ok {
+		parsedTags := helper.ParseTags(tags)
+		updateRequest.Tags = &parsedTags
+	}
+
 	if err := h.validator.Struct(updateRequest); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	product, err := h.productService.Update(updateRequest, c)
+	product, err := h.productService.Update(updateRequest, principal)
 	switch err {
 	case service.ErrProductNotFound:
 		return echo.NewHTTPError(http.StatusNotFound, "Product not found")
 	case service.ErrDontOwnProduct:
 		return echo.NewHTTPError(http.StatusBadRequest, "You don't own this product")
+	case service.ErrCategoryNotFound:
+		return echo.NewHTTPError(http.StatusBadRequest, "Category not found")
 	case nil:
 		return c.JSON(http.StatusOK, product)
 	default:
@@ -121,6 +221,11 @@ func (h *ProductHandler) UpdateCurrentStoreProduct(c echo.Context) error {
 }
 
 func (h *ProductHandler) Buy(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
 	quantity, err := strconv.ParseInt(c.FormValue("quantity"), 10, 32)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid quantity")
@@ -129,22 +234,33 @@ func (h *ProductHandler) Buy(c echo.Context) error {
 	transactionRequest := model.TransactionCreate{
 		ProductID: c.Param("id"),
 		Quantity:  int(quantity),
+		AddressID: c.FormValue("address_id"),
 	}
 
 	if err := h.validator.Struct(transactionRequest); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	transaction, err := h.productService.Buy(transactionRequest, c)
+	transaction, err := h.productService.Buy(transactionRequest, principal)
 	switch err {
 	case service.ErrProductNotFound:
 		return echo.NewHTTPError(http.StatusNotFound, "Product not found")
+	case service.ErrInvalidQuantity:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid quantity")
 	case service.ErrInsufficientStock:
 		return echo.NewHTTPError(http.StatusBadRequest, "You buy more than the available stock")
 	case service.ErrInsufficientBalance:
 		return echo.NewHTTPError(http.StatusBadRequest, "You don't have enough balance to buy this product")
 	case service.ErrBuyYourOwnProduct:
 		return echo.NewHTTPError(http.StatusBadRequest, "You can't buy your own product")
+	case service.ErrEmailNotVerified:
+		return echo.NewHTTPError(http.StatusForbidden, "Please verify your email address first")
+	case service.ErrAddressNotFound:
+		return echo.NewHTTPError(http.StatusBadRequest, "Address not found")
+	case service.ErrNotShippingAddress:
+		return echo.NewHTTPError(http.StatusBadRequest, "Only shipping addresses can be used for a purchase")
+	case service.ErrExchangeRateNotFound:
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "This product can't be bought in your currency right now")
 	case nil:
 		return c.JSON(http.StatusCreated, transaction)
 	default:
@@ -152,3 +268,28 @@ func (h *ProductHandler) Buy(c echo.Context) error {
 		return echo.ErrInternalServerError
 	}
 }
+
+func formValueIfPresent(c echo.Context, name string) (string, bool) {
+	params, err := c.FormParams()
+	if err != nil {
+		return "", false
+	}
+
+	if _, ok := params[name]; !ok {
+		return "", false
+	}
+
+	return params.Get(name), true
+}
+
+func requirePriceCurrency(filter model.ProductFilter, query model.ListQuery) error {
+	if filter.Currency != "" {
+		return nil
+	}
+
+	if filter.MinPrice != nil || filter.MaxPrice != nil || strings.TrimPrefix(query.Sort, "-") == "price" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Currency is required to filter or sort by price")
+	}
+
+	return nil
+}
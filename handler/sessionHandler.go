@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/service"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type SessionHandler struct {
+	database       *database.Database
+	validator      *validator.Validate
+	sessionService *service.SessionService
+}
+
+func NewSessionHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	sessionService *service.SessionService,
+) *SessionHandler {
+	return &SessionHandler{
+		database:       database,
+		validator:      validator,
+		sessionService: sessionService,
+	}
+}
+
+func (h *SessionHandler) GetAllCurrentUserSession(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	var currentToken string
+	if cookie, err := c.Cookie("refresh_token"); err == nil {
+		currentToken = cookie.Value
+	}
+
+	sessions, err := h.sessionService.GetAllByUserEmail(principal.Email, currentToken)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, sessions)
+}
+
+func (h *SessionHandler) RevokeCurrentUserSession(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	sessionID := c.Param("id")
+	if err := h.validator.Var(sessionID, "uuid"); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Session not found")
+	}
+
+	err = h.sessionService.Revoke(principal.Email, sessionID)
+	switch err {
+	case service.ErrSessionNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Session not found")
+	case nil:
+		return c.NoContent(http.StatusOK)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *SessionHandler) RevokeAllCurrentUserSession(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	if err := h.sessionService.RevokeAll(principal.Email); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	helper.ClearRefreshTokenCookies(c)
+
+	return c.NoContent(http.StatusOK)
+}
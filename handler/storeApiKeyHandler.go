@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/service"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type StoreApiKeyHandler struct {
+	database           *database.Database
+	validator          *validator.Validate
+	storeApiKeyService *service.StoreApiKeyService
+}
+
+func NewStoreApiKeyHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	storeApiKeyService *service.StoreApiKeyService,
+) *StoreApiKeyHandler {
+	return &StoreApiKeyHandler{
+		database:           database,
+		validator:          validator,
+		storeApiKeyService: storeApiKeyService,
+	}
+}
+
+func (h *StoreApiKeyHandler) CreateCurrentStoreApiKey(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	var scopes []string
+	if err := echo.FormFieldBinder(c).Strings("scopes", &scopes).BindError(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid scopes")
+	}
+
+	createRequest := model.StoreApiKeyCreate{
+		Name:   c.FormValue("name"),
+		Scopes: scopes,
+	}
+
+	if err := h.validator.Struct(createRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	apiKey, err := h.storeApiKeyService.Create(principal.Email, createRequest)
+	switch err {
+	case service.ErrDontHaveStore:
+		return echo.NewHTTPError(http.StatusBadRequest, "You don't have a store yet")
+	case nil:
+		return c.JSON(http.StatusCreated, apiKey)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *StoreApiKeyHandler) GetAllCurrentStoreApiKey(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	apiKeys, err := h.storeApiKeyService.GetAll(principal.Email)
+	switch err {
+	case service.ErrDontHaveStore:
+		return echo.NewHTTPError(http.StatusBadRequest, "You don't have a store yet")
+	case nil:
+		return c.JSON(http.StatusOK, apiKeys)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *StoreApiKeyHandler) RevokeCurrentStoreApiKey(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	apiKey, err := h.storeApiKeyService.Revoke(principal.Email, c.Param("id"))
+	switch err {
+	case service.ErrDontHaveStore:
+		return echo.NewHTTPError(http.StatusBadRequest, "You don't have a store yet")
+	case service.ErrApiKeyNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "API key not found")
+	case nil:
+		return c.JSON(http.StatusOK, apiKey)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
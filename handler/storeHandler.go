@@ -5,6 +5,7 @@ import (
 	"ecommerce-api/helper"
 	"ecommerce-api/model"
 	"net/http"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -24,9 +25,23 @@ func NewStoreHandler(database *database.Database, validator *validator.Validate)
 }
 
 func (h *StoreHandler) GetAll(c echo.Context) error {
-	stores, err := model.GetAllStore(h.database.Conn)
+	query, err := bindListQuery(c)
 	if err != nil {
-		return echo.ErrInternalServerError
+		return err
+	}
+
+	filter := model.StoreFilter{
+		OwnerEmail: c.QueryParam("owner_email"),
+		Currency:   strings.ToUpper(c.QueryParam("currency")),
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	stores, err := model.GetAllStore(h.database.Conn, filter, query)
+	if err != nil {
+		return listQueryError(err)
 	}
 
 	return c.JSON(http.StatusOK, stores)
@@ -45,30 +60,70 @@ func (h *StoreHandler) GetByID(c echo.Context) error {
 }
 
 func (h *StoreHandler) CreateCurrentUserStore(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
 	registerRequest := model.StoreRegister{
-		Name: c.FormValue("name"),
+		Name:     c.FormValue("name"),
+		Currency: strings.ToUpper(c.FormValue("currency")),
 	}
 
 	if err := h.validator.Struct(registerRequest); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	owner := model.User{
+		Email: principal.Email,
+	}
+	if err := owner.GetByEmail(h.database.Conn); err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	if !owner.IsEmailVerified() {
+		return echo.NewHTTPError(http.StatusForbidden, "Please verify your email address first")
+	}
+
 	store := registerRequest.ToStore()
-	store.OwnerEmail = helper.ExtractJwtEmail(c)
+	store.OwnerEmail = owner.Email
+	if store.Currency == "" {
+		store.Currency = owner.Balance.Currency
+	}
+
+	tx, err := h.database.Conn.Begin()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
 
-	if err := store.Create(h.database.Conn); err != nil {
+	if err := store.Create(tx); err != nil {
+		tx.Rollback()
 		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
 			return echo.NewHTTPError(http.StatusBadRequest, "This account already has a store")
 		}
 		return echo.ErrInternalServerError
 	}
 
+	if err := owner.AddRole(tx, model.RoleSeller); err != nil {
+		tx.Rollback()
+		return echo.ErrInternalServerError
+	}
+
+	if err := tx.Commit(); err != nil {
+		return echo.ErrInternalServerError
+	}
+
 	return c.JSON(http.StatusCreated, store)
 }
 
 func (h *StoreHandler) GetCurrent(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
 	store := model.Store{
-		OwnerEmail: helper.ExtractJwtEmail(c),
+		OwnerEmail: principal.Email,
 	}
 
 	if err := store.GetByOwnerEmail(h.database.Conn); err != nil {
@@ -79,6 +134,11 @@ func (h *StoreHandler) GetCurrent(c echo.Context) error {
 }
 
 func (h *StoreHandler) UpdateCurrent(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
 	updateRequest := model.StoreRegister{
 		Name: c.FormValue("name"),
 	}
@@ -88,7 +148,7 @@ func (h *StoreHandler) UpdateCurrent(c echo.Context) error {
 	}
 
 	store := updateRequest.ToStore()
-	store.OwnerEmail = helper.ExtractJwtEmail(c)
+	store.OwnerEmail = principal.Email
 
 	if err := store.UpdateByOwnerEmail(h.database.Conn); err != nil {
 		return echo.ErrInternalServerError
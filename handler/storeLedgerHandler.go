@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/service"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type StoreLedgerHandler struct {
+	database           *database.Database
+	validator          *validator.Validate
+	storeLedgerService *service.StoreLedgerService
+}
+
+func NewStoreLedgerHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	storeLedgerService *service.StoreLedgerService,
+) *StoreLedgerHandler {
+	return &StoreLedgerHandler{
+		database:           database,
+		validator:          validator,
+		storeLedgerService: storeLedgerService,
+	}
+}
+
+func (h *StoreLedgerHandler) GetCurrentBalance(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	balance, err := h.storeLedgerService.GetBalance(principal)
+	switch err {
+	case service.ErrDontHaveStore:
+		return echo.NewHTTPError(http.StatusBadRequest, "You don't have a store yet")
+	case nil:
+		return c.JSON(http.StatusOK, balance)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *StoreLedgerHandler) GetCurrentEarnings(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	earnings, err := h.storeLedgerService.GetEarnings(principal)
+	switch err {
+	case service.ErrDontHaveStore:
+		return echo.NewHTTPError(http.StatusBadRequest, "You don't have a store yet")
+	case nil:
+		return c.JSON(http.StatusOK, earnings)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *StoreLedgerHandler) ReleaseOpeningBalance(c echo.Context) error {
+	storeID := c.Param("id")
+	if err := h.validator.Var(storeID, "uuid"); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Opening balance not found")
+	}
+
+	entry, err := h.storeLedgerService.ReleaseOpeningBalance(storeID)
+	switch err {
+	case service.ErrOpeningBalanceNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Opening balance not found")
+	case service.ErrOpeningBalanceAlreadyReleased:
+		return echo.NewHTTPError(http.StatusConflict, "Opening balance already released")
+	case nil:
+		return c.JSON(http.StatusCreated, entry)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
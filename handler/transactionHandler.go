@@ -4,6 +4,7 @@ import (
 	"ecommerce-api/database"
 	"ecommerce-api/helper"
 	"ecommerce-api/model"
+	"ecommerce-api/service"
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
@@ -11,31 +12,56 @@ import (
 )
 
 type TransactionHandler struct {
-	database  *database.Database
-	validator *validator.Validate
+	database           *database.Database
+	validator          *validator.Validate
+	transactionService *service.TransactionService
 }
 
 func NewTransactionHandler(
 	database *database.Database,
 	validator *validator.Validate,
+	transactionService *service.TransactionService,
 ) *TransactionHandler {
 	return &TransactionHandler{
-		database:  database,
-		validator: validator,
+		database:           database,
+		validator:          validator,
+		transactionService: transactionService,
 	}
 }
 
 func (h *TransactionHandler) GetAll(c echo.Context) error {
-	stores, err := model.GetAllTransaction(h.database.Conn)
+	query, err := bindListQuery(c)
 	if err != nil {
-		return echo.ErrInternalServerError
+		return err
+	}
+
+	filter := model.TransactionFilter{
+		UserEmail: c.QueryParam("user_email"),
+		ProductID: c.QueryParam("product_id"),
+	}
+	if filter.Refunded, err = queryBool(c, "refunded"); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	transactions, err := model.GetAllTransaction(h.database.Conn, filter, query)
+	if err != nil {
+		return listQueryError(err)
 	}
 
-	return c.JSON(http.StatusOK, stores)
+	return c.JSON(http.StatusOK, transactions)
 }
 
 func (h *TransactionHandler) GetAllCurrentUserTransaction(c echo.Context) error {
-	transactions, err := model.GetAllTransactionByUserEmail(h.database.Conn, helper.ExtractJwtEmail(c))
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	transactions, err := model.GetAllTransactionByUserEmail(h.database.Conn, principal.Email)
 	if err != nil {
 		return echo.ErrInternalServerError
 	}
@@ -54,3 +80,17 @@ func (h *TransactionHandler) GetByID(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, transaction)
 }
+
+func (h *TransactionHandler) Refund(c echo.Context) error {
+	transaction, err := h.transactionService.Refund(c.Param("id"))
+	switch err {
+	case service.ErrTransactionNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Transaction not found")
+	case service.ErrTransactionAlreadyRefunded:
+		return echo.NewHTTPError(http.StatusBadRequest, "Transaction already refunded")
+	case nil:
+		return c.JSON(http.StatusOK, transaction)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
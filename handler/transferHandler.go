@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/money"
+	"ecommerce-api/service"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type TransferHandler struct {
+	database        *database.Database
+	validator       *validator.Validate
+	transferService *service.TransferService
+}
+
+func NewTransferHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	transferService *service.TransferService,
+) *TransferHandler {
+	return &TransferHandler{
+		database:        database,
+		validator:       validator,
+		transferService: transferService,
+	}
+}
+
+func (h *TransferHandler) CreateCurrentUserTransfer(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	amount, err := strconv.ParseInt(c.FormValue("amount"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid amount")
+	}
+
+	createRequest := model.TransferCreate{
+		RecipientEmail: helper.NormalizeEmail(c.FormValue("recipient_email")),
+		Amount:         amount,
+		IdempotencyKey: c.Request().Header.Get("Idempotency-Key"),
+	}
+
+	if err := h.validator.Struct(createRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	transfer, replayed, err := h.transferService.Transfer(principal, createRequest)
+	switch err {
+	case service.ErrTransferToYourself:
+		return echo.NewHTTPError(http.StatusBadRequest, "You can't transfer to yourself")
+	case service.ErrRecipientNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Recipient not found")
+	case service.ErrEmailNotVerified:
+		return echo.NewHTTPError(http.StatusForbidden, "Please verify your email address first")
+	case service.ErrInsufficientBalance:
+		return echo.NewHTTPError(http.StatusBadRequest, "You don't have enough balance for this transfer")
+	case service.ErrTransferLimitExceeded:
+		return echo.NewHTTPError(http.StatusBadRequest, "This transfer exceeds your daily transfer limit")
+	case money.ErrCurrencyMismatch:
+		return echo.NewHTTPError(http.StatusBadRequest, "You can only transfer to users with the same wallet currency")
+	case service.ErrIdempotencyKeyReused:
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "Idempotency key was already used for a different transfer")
+	case nil:
+		if replayed {
+			return c.JSON(http.StatusOK, transfer)
+		}
+		return c.JSON(http.StatusCreated, transfer)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *TransferHandler) GetAllCurrentUserTransfer(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	transfers, err := h.transferService.GetAllByUserEmail(principal.Email)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, transfers)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/service"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type TwoFactorHandler struct {
+	database         *database.Database
+	validator        *validator.Validate
+	twoFactorService *service.TwoFactorService
+}
+
+func NewTwoFactorHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	twoFactorService *service.TwoFactorService,
+) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		database:         database,
+		validator:        validator,
+		twoFactorService: twoFactorService,
+	}
+}
+
+func (h *TwoFactorHandler) Enroll(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	enrollment, err := h.twoFactorService.Enroll(principal.Email)
+	switch err {
+	case service.ErrTwoFactorAlreadyEnabled:
+		return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication is already enabled")
+	case nil:
+		return c.JSON(http.StatusOK, enrollment)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *TwoFactorHandler) Confirm(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	confirmRequest := model.TotpConfirm{
+		Code: c.FormValue("code"),
+	}
+
+	if err := h.validator.Struct(confirmRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	recoveryCodes, err := h.twoFactorService.Confirm(principal.Email, confirmRequest)
+	switch err {
+	case service.ErrTwoFactorNotEnrolled:
+		return echo.NewHTTPError(http.StatusBadRequest, "Start two-factor enrollment first")
+	case service.ErrTwoFactorAlreadyEnabled:
+		return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication is already enabled")
+	case service.ErrInvalidTwoFactorCode:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid two-factor code")
+	case nil:
+		return c.JSON(http.StatusOK, echo.Map{
+			"recovery_codes": recoveryCodes,
+		})
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *TwoFactorHandler) Disable(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	disableRequest := model.TotpDisable{
+		Password: c.FormValue("password"),
+		Code:     c.FormValue("code"),
+	}
+
+	if err := h.validator.Struct(disableRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	switch err := h.twoFactorService.Disable(principal.Email, disableRequest); err {
+	case service.ErrWrongPassword:
+		return echo.NewHTTPError(http.StatusBadRequest, "Current password is incorrect")
+	case service.ErrTwoFactorNotEnabled:
+		return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication is not enabled")
+	case service.ErrInvalidTwoFactorCode:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid two-factor code")
+	case nil:
+		return c.NoContent(http.StatusOK)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
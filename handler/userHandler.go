@@ -6,50 +6,59 @@ import (
 	"ecommerce-api/model"
 	"ecommerce-api/service"
 	"net/http"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 )
 
 type UserHandler struct {
-	database    *database.Database
-	validator   *validator.Validate
-	authService *service.AuthService
-	userService *service.UserService
+	database       *database.Database
+	validator      *validator.Validate
+	passwordPolicy helper.PasswordPolicy
+	authService    *service.AuthService
+	userService    *service.UserService
 }
 
 func NewUserHandler(
 	database *database.Database,
 	validator *validator.Validate,
+	passwordPolicy helper.PasswordPolicy,
 	authService *service.AuthService,
 	userService *service.UserService,
 ) *UserHandler {
 	return &UserHandler{
-		database:    database,
-		validator:   validator,
-		authService: authService,
-		userService: userService,
+		database:       database,
+		validator:      validator,
+		passwordPolicy: passwordPolicy,
+		authService:    authService,
+		userService:    userService,
 	}
 }
 
 func (h *UserHandler) Register(c echo.Context) error {
 	registerRequest := model.UserRegister{
-		Email:     c.FormValue("email"),
+		Email:     helper.NormalizeEmail(c.FormValue("email")),
 		FirstName: c.FormValue("first_name"),
 		LastName:  c.FormValue("last_name"),
 		Password:  c.FormValue("password"),
+		Currency:  strings.ToUpper(c.FormValue("currency")),
 	}
 
 	if err := h.validator.Struct(registerRequest); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	if err := h.passwordPolicy.Validate(registerRequest.Password); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
 	user, err := h.userService.Register(registerRequest)
 	switch err {
 	case service.ErrEmailAlreadyTaken:
 		return echo.NewHTTPError(http.StatusBadRequest, "Email already taken")
 	case nil:
-		return c.JSON(http.StatusOK, user)
+		return c.JSON(http.StatusOK, serializeUser(c, user))
 	default:
 		return echo.ErrInternalServerError
 	}
@@ -57,35 +66,64 @@ func (h *UserHandler) Register(c echo.Context) error {
 
 func (h *UserHandler) GetByEmail(c echo.Context) error {
 	user := model.User{
-		Email: c.Param("email"),
+		Email: helper.NormalizeEmail(c.Param("email")),
 	}
 
 	if err := user.GetByEmail(h.database.Conn); err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "User not found")
 	}
 
-	return c.JSON(http.StatusOK, user)
+	return c.JSON(http.StatusOK, serializeUser(c, user))
 }
 
 func (h *UserHandler) GetAll(c echo.Context) error {
-	users, err := model.GetAllUsers(h.database.Conn)
+	query, err := bindListQuery(c)
 	if err != nil {
-		return echo.ErrInternalServerError
+		return err
 	}
 
-	return c.JSON(http.StatusOK, users)
+	filter := model.UserFilter{
+		Role: c.QueryParam("role"),
+	}
+	if filter.Banned, err = queryBool(c, "banned"); err != nil {
+		return err
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	users, err := model.GetAllUsers(h.database.Conn, filter, query)
+	if err != nil {
+		return listQueryError(err)
+	}
+
+	return c.JSON(http.StatusOK, model.ListPage[interface{}]{
+		Items:      serializeUsers(c, users.Items),
+		NextCursor: users.NextCursor,
+	})
 }
 
 func (h *UserHandler) GetCurrent(c echo.Context) error {
-	user, err := h.authService.CurrentUser(c)
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	user, err := h.authService.CurrentUser(principal)
 	if err != nil {
 		return echo.ErrUnauthorized
 	}
 
-	return c.JSON(http.StatusOK, user)
+	return c.JSON(http.StatusOK, serializeUser(c, user))
 }
 
 func (h *UserHandler) UpdateCurrent(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
 	updateRequest := model.UserUpdate{
 		FirstName: c.FormValue("first_name"),
 		LastName:  c.FormValue("last_name"),
@@ -96,8 +134,189 @@ func (h *UserHandler) UpdateCurrent(c echo.Context) error {
 	}
 
 	user := updateRequest.ToUser()
-	user.Email = helper.ExtractJwtEmail(c)
+	user.Email = principal.Email
 	user.Update(h.database.Conn)
 
-	return c.JSON(http.StatusOK, user)
+	return c.JSON(http.StatusOK, serializeUser(c, user))
+}
+
+func (h *UserHandler) UpdateRoles(c echo.Context) error {
+	var roles []string
+	if err := echo.FormFieldBinder(c).Strings("roles", &roles).BindError(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid roles")
+	}
+
+	updateRequest := model.UserRolesUpdate{
+		Email: helper.NormalizeEmail(c.Param("email")),
+		Roles: roles,
+	}
+
+	if err := h.validator.Struct(updateRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	user := updateRequest.ToUser()
+	if err := user.UpdateRoles(h.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return echo.NewHTTPError(http.StatusNotFound, "User not found")
+		}
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, serializeUser(c, user))
+}
+
+func (h *UserHandler) VerifyEmail(c echo.Context) error {
+	verifyRequest := model.EmailVerify{
+		Token: c.FormValue("token"),
+	}
+
+	if err := h.validator.Struct(verifyRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	user, err := h.userService.VerifyEmail(verifyRequest)
+	switch err {
+	case service.ErrInvalidVerifyToken:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired verification token")
+	case nil:
+		return c.JSON(http.StatusOK, serializeUser(c, user))
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *UserHandler) ResendCurrentVerificationEmail(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	switch err := h.userService.ResendVerificationEmail(principal.Email); err {
+	case service.ErrEmailAlreadyVerified:
+		return echo.NewHTTPError(http.StatusBadRequest, "Email already verified")
+	case nil:
+		return c.NoContent(http.StatusOK)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *UserHandler) ChangeCurrentPassword(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	changeRequest := model.UserPasswordChange{
+		CurrentPassword: c.FormValue("current_password"),
+		NewPassword:     c.FormValue("new_password"),
+	}
+
+	if err := h.validator.Struct(changeRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.passwordPolicy.Validate(changeRequest.NewPassword); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var currentToken string
+	if cookie, err := c.Cookie("refresh_token"); err == nil {
+		currentToken = cookie.Value
+	}
+
+	switch err := h.userService.ChangePassword(principal.Email, changeRequest, currentToken); err {
+	case service.ErrWrongPassword:
+		return echo.NewHTTPError(http.StatusBadRequest, "Current password is incorrect")
+	case nil:
+		return c.NoContent(http.StatusOK)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *UserHandler) GetAllLockout(c echo.Context) error {
+	lockouts, err := h.authService.GetAllActiveLockout()
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, lockouts)
+}
+
+func (h *UserHandler) Unlock(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	if err := h.authService.Unlock(helper.NormalizeEmail(c.Param("email")), principal.Email); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *UserHandler) Ban(c echo.Context) error {
+	user, err := h.userService.Ban(helper.NormalizeEmail(c.Param("email")))
+	switch err {
+	case service.ErrUserNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	case nil:
+		return c.JSON(http.StatusOK, serializeUser(c, user))
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *UserHandler) Unban(c echo.Context) error {
+	user, err := h.userService.Unban(helper.NormalizeEmail(c.Param("email")))
+	switch err {
+	case service.ErrUserNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	case nil:
+		return c.JSON(http.StatusOK, serializeUser(c, user))
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *UserHandler) ExportCurrent(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	export, err := h.userService.Export(principal.Email)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if c.QueryParam("format") != "zip" {
+		return c.JSON(http.StatusOK, export)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="export.zip"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	return export.WriteZip(c.Response())
+}
+
+func (h *UserHandler) DeleteCurrent(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	switch err := h.userService.Delete(c.Request().Context(), principal); err {
+	case service.ErrUserNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	case nil:
+		helper.ClearRefreshTokenCookies(c)
+		return c.NoContent(http.StatusOK)
+	default:
+		return echo.ErrInternalServerError
+	}
 }
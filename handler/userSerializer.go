@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+func serializeUser(c echo.Context, user model.User) interface{} {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil || principal.IsApiKey() {
+		return user.ToPublic()
+	}
+
+	if principal.Email == user.Email || principal.HasRole(model.RoleAdmin) {
+		return user.ToPrivate()
+	}
+
+	return user.ToPublic()
+}
+
+func serializeUsers(c echo.Context, users []model.User) []interface{} {
+	serialized := make([]interface{}, 0, len(users))
+	for _, user := range users {
+		serialized = append(serialized, serializeUser(c, user))
+	}
+
+	return serialized
+}
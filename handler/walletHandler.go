@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/service"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type WalletHandler struct {
+	database      *database.Database
+	validator     *validator.Validate
+	walletService *service.WalletService
+}
+
+func NewWalletHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	walletService *service.WalletService,
+) *WalletHandler {
+	return &WalletHandler{
+		database:      database,
+		validator:     validator,
+		walletService: walletService,
+	}
+}
+
+func (h *WalletHandler) TopUpCurrent(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	amount, err := strconv.ParseInt(c.FormValue("amount"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid amount")
+	}
+
+	topUpRequest := model.WalletTopUp{
+		Amount: amount,
+	}
+
+	if err := h.validator.Struct(topUpRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	topUp, err := h.walletService.TopUp(c.Request().Context(), principal.Email, topUpRequest)
+	switch err {
+	case service.ErrUserNotFound:
+		return echo.ErrUnauthorized
+	case service.ErrTopUpLimitExceeded:
+		return echo.NewHTTPError(http.StatusBadRequest, "Daily top-up limit exceeded")
+	case service.ErrPaymentFailed:
+		return echo.NewHTTPError(http.StatusBadGateway, "The payment provider rejected the top-up")
+	case nil:
+		return c.JSON(http.StatusCreated, topUp)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *WalletHandler) ConfirmCurrentTopUp(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	topUpID := c.Param("id")
+	if err := h.validator.Var(topUpID, "uuid"); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Top-up not found")
+	}
+
+	topUp, err := h.walletService.ConfirmTopUp(c.Request().Context(), principal.Email, topUpID)
+	switch err {
+	case service.ErrTopUpNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Top-up not found")
+	case service.ErrTopUpPending:
+		return echo.NewHTTPError(http.StatusConflict, "The payment has not been confirmed yet")
+	case service.ErrPaymentFailed:
+		return echo.NewHTTPError(http.StatusBadGateway, "Could not check the payment status, retry later")
+	case nil:
+		return c.JSON(http.StatusOK, topUp)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *WalletHandler) GetAllCurrentTopUp(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	topUps, err := h.walletService.GetAllTopUp(principal.Email)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, topUps)
+}
+
+func (h *WalletHandler) GetAllCurrentLedgerEntry(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	entries, err := h.walletService.GetAllLedgerEntry(principal.Email)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (h *WalletHandler) Adjust(c echo.Context) error {
+	amount, err := strconv.ParseInt(c.FormValue("amount"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid amount")
+	}
+
+	adjustmentRequest := model.WalletAdjustment{
+		Amount:      amount,
+		Description: c.FormValue("description"),
+	}
+
+	if err := h.validator.Struct(adjustmentRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	entry, err := h.walletService.Adjust(helper.NormalizeEmail(c.Param("email")), adjustmentRequest)
+	switch err {
+	case service.ErrUserNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	case service.ErrInsufficientBalance:
+		return echo.NewHTTPError(http.StatusBadRequest, "Adjustment would make the balance negative")
+	case nil:
+		return c.JSON(http.StatusCreated, entry)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
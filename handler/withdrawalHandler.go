@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/service"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type WithdrawalHandler struct {
+	database          *database.Database
+	validator         *validator.Validate
+	withdrawalService *service.WithdrawalService
+}
+
+func NewWithdrawalHandler(
+	database *database.Database,
+	validator *validator.Validate,
+	withdrawalService *service.WithdrawalService,
+) *WithdrawalHandler {
+	return &WithdrawalHandler{
+		database:          database,
+		validator:         validator,
+		withdrawalService: withdrawalService,
+	}
+}
+
+func (h *WithdrawalHandler) RequestCurrentStoreWithdrawal(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	amount, err := strconv.ParseInt(c.FormValue("amount"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid amount")
+	}
+
+	createRequest := model.WithdrawalCreate{
+		Amount: amount,
+	}
+
+	if err := h.validator.Struct(createRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	withdrawal, err := h.withdrawalService.Request(principal, createRequest)
+	switch err {
+	case service.ErrDontHaveStore:
+		return echo.NewHTTPError(http.StatusBadRequest, "You don't have a store yet")
+	case service.ErrInsufficientBalance:
+		return echo.NewHTTPError(http.StatusBadRequest, "Your store doesn't have enough available balance")
+	case nil:
+		return c.JSON(http.StatusCreated, withdrawal)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *WithdrawalHandler) GetAllCurrentStoreWithdrawal(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	withdrawals, err := h.withdrawalService.GetAllCurrent(principal)
+	switch err {
+	case service.ErrDontHaveStore:
+		return echo.NewHTTPError(http.StatusBadRequest, "You don't have a store yet")
+	case nil:
+		return c.JSON(http.StatusOK, withdrawals)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *WithdrawalHandler) GetAll(c echo.Context) error {
+	withdrawals, err := h.withdrawalService.GetAll(c.QueryParam("status"))
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, withdrawals)
+}
+
+func (h *WithdrawalHandler) Approve(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	withdrawal, err := h.withdrawalService.Approve(c.Request().Context(), c.Param("id"), principal.Email)
+	switch err {
+	case service.ErrWithdrawalNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Withdrawal not found")
+	case service.ErrWithdrawalNotPending:
+		return echo.NewHTTPError(http.StatusBadRequest, "Withdrawal is no longer pending")
+	case service.ErrWithdrawalNotApproved:
+		return echo.NewHTTPError(http.StatusConflict, "Payout was sent but the withdrawal is no longer approved")
+	case service.ErrPayoutFailed:
+		return echo.NewHTTPError(http.StatusBadGateway, "Withdrawal approved but the payout failed, retry or mark it as failed")
+	case nil:
+		return c.JSON(http.StatusOK, withdrawal)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *WithdrawalHandler) Reject(c echo.Context) error {
+	principal, err := helper.CurrentPrincipal(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	rejectRequest := model.WithdrawalReject{
+		Reason: c.FormValue("reason"),
+	}
+
+	if err := h.validator.Struct(rejectRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	withdrawal, err := h.withdrawalService.Reject(c.Param("id"), principal.Email, rejectRequest)
+	switch err {
+	case service.ErrWithdrawalNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Withdrawal not found")
+	case service.ErrWithdrawalNotPending:
+		return echo.NewHTTPError(http.StatusBadRequest, "Withdrawal is no longer pending")
+	case nil:
+		return c.JSON(http.StatusOK, withdrawal)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
+
+func (h *WithdrawalHandler) MarkFailed(c echo.Context) error {
+	failRequest := model.WithdrawalFail{
+		Reason: c.FormValue("reason"),
+	}
+
+	if err := h.validator.Struct(failRequest); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	withdrawal, err := h.withdrawalService.MarkFailed(c.Param("id"), failRequest)
+	switch err {
+	case service.ErrWithdrawalNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, "Withdrawal not found")
+	case service.ErrWithdrawalNotApproved:
+		return echo.NewHTTPError(http.StatusBadRequest, "Only approved withdrawals can be marked as failed")
+	case nil:
+		return c.JSON(http.StatusOK, withdrawal)
+	default:
+		return echo.ErrInternalServerError
+	}
+}
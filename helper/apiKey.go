@@ -0,0 +1,22 @@
+package helper
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+func GenerateApiKey() (string, string, error) {
+	prefixBytes := make([]byte, 4)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+
+	secret, err := GenerateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix := "sk_" + hex.EncodeToString(prefixBytes)
+
+	return prefix + "_" + secret, prefix, nil
+}
@@ -0,0 +1,57 @@
+package helper
+
+import (
+	"image"
+	"image/color"
+)
+
+func ResizeSquare(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+
+	cropX := bounds.Min.X + (bounds.Dx()-side)/2
+	cropY := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		y0 := cropY + y*side/size
+		y1 := cropY + (y+1)*side/size
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < size; x++ {
+			x0 := cropX + x*side/size
+			x1 := cropX + (x+1)*side/size
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					count++
+				}
+			}
+
+			background := 0xffff - a/count
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r/count + background) >> 8),
+				G: uint8((g/count + background) >> 8),
+				B: uint8((b/count + background) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
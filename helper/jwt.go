@@ -8,43 +8,32 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-type JwtData struct {
-	Email string
-}
+const AccessTokenLifetime = time.Hour * 3
 
 type JwtCustomClaims struct {
-	Email string `json:"email,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	SessionID string   `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func getJwtValue(c echo.Context, key string) (string, bool) {
-	jwtToken := c.Get("user").(*jwt.Token)
-	claims := jwtToken.Claims.(jwt.MapClaims)
-
-	if claims[key] != nil {
-		return claims[key].(string), true
+func GenerateJwtToken(email string, roles []string, sessionID string, keySet *JwtKeySet) (string, error) {
+	tokenID, err := GenerateRandomToken()
+	if err != nil {
+		return "", err
 	}
 
-	return "", false
-}
-
-func ExtractJwtEmail(c echo.Context) string {
-	if value, ok := getJwtValue(c, "email"); ok {
-		return value
-	}
-	return ""
-}
-
-func GenerateJwtToken(email string, jwtKey []byte) (string, error) {
 	claims := JwtCustomClaims{
-		Email: email,
+		Email:     email,
+		Roles:     roles,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 3)),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenLifetime)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtKey)
+	return keySet.Sign(claims)
 }
 
 func AssignRefreshTokenCookes(token string, c echo.Context) {
@@ -0,0 +1,263 @@
+package helper
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrUnknownJwtKey = errors.New("Unknown jwt key")
+
+type JwtKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	SigningKey interface{}
+	VerifyKey  interface{}
+}
+
+type JwtKeySet struct {
+	keys         map[string]*JwtKey
+	signingKeyID string
+}
+
+type Jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JwkSet struct {
+	Keys []Jwk `json:"keys"`
+}
+
+const HmacJwtKeyID = "default"
+
+func NewHmacJwtKey(secret []byte) *JwtKey {
+	return &JwtKey{
+		ID:         HmacJwtKeyID,
+		Method:     jwt.SigningMethodHS256,
+		SigningKey: secret,
+		VerifyKey:  secret,
+	}
+}
+
+func NewHmacJwtKeySet(secret []byte) *JwtKeySet {
+	return &JwtKeySet{
+		keys: map[string]*JwtKey{
+			HmacJwtKeyID: NewHmacJwtKey(secret),
+		},
+		signingKeyID: HmacJwtKeyID,
+	}
+}
+
+func LoadJwtKeySet(dir string, signingKeyID string) (*JwtKeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	retiredPaths, err := filepath.Glob(filepath.Join(dir, "retired", "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keySet := &JwtKeySet{
+		keys: map[string]*JwtKey{},
+	}
+
+	sort.Strings(paths)
+	for _, path := range paths {
+		key, err := loadJwtKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt key %s: %w", path, err)
+		}
+
+		if err := keySet.addKey(key); err != nil {
+			return nil, err
+		}
+		if signingKeyID == "" && key.SigningKey != nil {
+			keySet.signingKeyID = key.ID
+		}
+	}
+
+	sort.Strings(retiredPaths)
+	for _, path := range retiredPaths {
+		key, err := loadJwtKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt key %s: %w", path, err)
+		}
+
+		if err := keySet.AddVerifyKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	if signingKeyID != "" {
+		keySet.signingKeyID = signingKeyID
+	}
+
+	signingKey, ok := keySet.keys[keySet.signingKeyID]
+	if !ok || signingKey.SigningKey == nil {
+		return nil, fmt.Errorf("no active private signing key found in %s", dir)
+	}
+
+	return keySet, nil
+}
+
+func (k *JwtKeySet) AddVerifyKey(key *JwtKey) error {
+	return k.addKey(&JwtKey{
+		ID:        key.ID,
+		Method:    key.Method,
+		VerifyKey: key.VerifyKey,
+	})
+}
+
+func (k *JwtKeySet) addKey(key *JwtKey) error {
+	if _, ok := k.keys[key.ID]; ok {
+		return fmt.Errorf("duplicate jwt key id %s", key.ID)
+	}
+
+	k.keys[key.ID] = key
+
+	return nil
+}
+
+func loadJwtKey(path string) (*JwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid pem data")
+	}
+
+	key := &JwtKey{
+		ID: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported pem block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch parsedKey := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method = jwt.SigningMethodRS256
+		key.SigningKey = parsedKey
+		key.VerifyKey = &parsedKey.PublicKey
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+		key.VerifyKey = parsedKey
+	case ed25519.PrivateKey:
+		key.Method = jwt.SigningMethodEdDSA
+		key.SigningKey = parsedKey
+		key.VerifyKey = parsedKey.Public()
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+		key.VerifyKey = parsedKey
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	return key, nil
+}
+
+func (k *JwtKeySet) Sign(claims jwt.Claims) (string, error) {
+	key, ok := k.keys[k.signingKeyID]
+	if !ok {
+		return "", ErrUnknownJwtKey
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.SigningKey)
+}
+
+func (k *JwtKeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+	if keyID == "" {
+		if token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, ErrUnknownJwtKey
+		}
+		keyID = HmacJwtKeyID
+	}
+
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownJwtKey
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	return key.VerifyKey, nil
+}
+
+func (k *JwtKeySet) Jwks() JwkSet {
+	jwkSet := JwkSet{
+		Keys: []Jwk{},
+	}
+
+	keyIDs := make([]string, 0, len(k.keys))
+	for keyID := range k.keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+
+	for _, keyID := range keyIDs {
+		key := k.keys[keyID]
+
+		switch publicKey := key.VerifyKey.(type) {
+		case *rsa.PublicKey:
+			jwkSet.Keys = append(jwkSet.Keys, Jwk{
+				Kty: "RSA",
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Kid: key.ID,
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwkSet.Keys = append(jwkSet.Keys, Jwk{
+				Kty: "OKP",
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Kid: key.ID,
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	return jwkSet
+}
@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newRotatedTestKeySet(t *testing.T, secret []byte) (*JwtKeySet, *rsa.PrivateKey) {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keySet := &JwtKeySet{
+		keys: map[string]*JwtKey{
+			"rsa-1": {
+				ID:         "rsa-1",
+				Method:     jwt.SigningMethodRS256,
+				SigningKey: rsaKey,
+				VerifyKey:  &rsaKey.PublicKey,
+			},
+		},
+		signingKeyID: "rsa-1",
+	}
+	if err := keySet.AddVerifyKey(NewHmacJwtKey(secret)); err != nil {
+		t.Fatal(err)
+	}
+
+	return keySet, rsaKey
+}
+
+func TestKeyfuncAcceptsLegacyHmacTokenWithoutKid(t *testing.T) {
+	secret := []byte("legacy-secret")
+	keySet, _ := newRotatedTestKeySet(t, secret)
+
+	legacyToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "alice"}).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := jwt.Parse(legacyToken, keySet.Keyfunc); err != nil {
+		t.Fatalf("legacy token rejected: %v", err)
+	}
+
+	signedToken, err := keySet.Sign(jwt.RegisteredClaims{Subject: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := jwt.Parse(signedToken, keySet.Keyfunc); err != nil {
+		t.Fatalf("current token rejected: %v", err)
+	}
+}
+
+func TestKeyfuncRejectsNonHmacTokenWithoutKid(t *testing.T) {
+	keySet, rsaKey := newRotatedTestKeySet(t, []byte("legacy-secret"))
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{Subject: "alice"}).SignedString(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := jwt.Parse(token, keySet.Keyfunc); err == nil {
+		t.Fatal("token without kid was accepted with a non-HMAC algorithm")
+	}
+}
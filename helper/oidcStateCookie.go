@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const oidcStateCookieName = "oidc_state"
+
+func AssignOidcStateCookie(state string, c echo.Context) {
+	cookie := new(http.Cookie)
+	cookie.Name = oidcStateCookieName
+	cookie.Value = HashToken(state)
+	cookie.Path = "/auth/oidc"
+	cookie.Expires = time.Now().Add(10 * time.Minute)
+	cookie.HttpOnly = true
+	cookie.SameSite = http.SameSiteLaxMode
+	c.SetCookie(cookie)
+}
+
+func OidcStateCookieMatches(state string, c echo.Context) bool {
+	cookie, err := c.Cookie(oidcStateCookieName)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(HashToken(state))) == 1
+}
+
+func ClearOidcStateCookie(c echo.Context) {
+	cookie := new(http.Cookie)
+	cookie.Name = oidcStateCookieName
+	cookie.Value = ""
+	cookie.Path = "/auth/oidc"
+	cookie.Expires = time.Now().Add(-time.Hour)
+	cookie.HttpOnly = true
+	cookie.SameSite = http.SameSiteLaxMode
+	c.SetCookie(cookie)
+}
@@ -0,0 +1,50 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+type PasswordPolicy struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+}
+
+func (p *PasswordPolicy) Validate(password string) error {
+	var hasUppercase, hasLowercase, hasDigit, hasSymbol bool
+
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUppercase = true
+		case unicode.IsLower(char):
+			hasLowercase = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSymbol = true
+		}
+	}
+
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("Password must be at least %d characters long", p.MinLength)
+	}
+	if p.RequireUppercase && !hasUppercase {
+		return errors.New("Password must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !hasLowercase {
+		return errors.New("Password must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		return errors.New("Password must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		return errors.New("Password must contain a symbol")
+	}
+
+	return nil
+}
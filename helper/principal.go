@@ -0,0 +1,64 @@
+package helper
+
+import (
+	"context"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+var ErrNoPrincipal = errors.New("No authenticated principal")
+
+type principalContextKey struct{}
+
+type Principal struct {
+	Email     string
+	Roles     []string
+	StoreID   string
+	TokenID   string
+	SessionID string
+	ApiKeyID  string
+	Scopes    []string
+}
+
+func (p Principal) HasRole(roles ...string) bool {
+	for _, principalRole := range p.Roles {
+		for _, role := range roles {
+			if principalRole == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p Principal) HasScope(scope string) bool {
+	for _, principalScope := range p.Scopes {
+		if principalScope == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Principal) IsApiKey() bool {
+	return p.ApiKeyID != ""
+}
+
+func SetPrincipal(c echo.Context, principal Principal) {
+	request := c.Request()
+	c.SetRequest(request.WithContext(context.WithValue(request.Context(), principalContextKey{}, principal)))
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, error) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	if !ok || (principal.Email == "" && principal.StoreID == "") {
+		return Principal{}, ErrNoPrincipal
+	}
+
+	return principal, nil
+}
+
+func CurrentPrincipal(c echo.Context) (Principal, error) {
+	return PrincipalFromContext(c.Request().Context())
+}
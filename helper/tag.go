@@ -0,0 +1,20 @@
+package helper
+
+import "strings"
+
+func ParseTags(value string) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+type LogMailer struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func NewLogMailer(writer io.Writer) *LogMailer {
+	return &LogMailer{
+		writer: writer,
+	}
+}
+
+func NewFileMailer(path string) (*LogMailer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogMailer(file), nil
+}
+
+func (m *LogMailer) Send(message Message) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, err := fmt.Fprintf(
+		m.writer,
+		"--- %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339),
+		message.To,
+		message.Subject,
+		message.Body,
+	)
+
+	return err
+}
@@ -0,0 +1,35 @@
+package mailer
+
+import "os"
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(message Message) error
+}
+
+type Config struct {
+	Driver       string
+	From         string
+	SmtpHost     string
+	SmtpPort     string
+	SmtpUsername string
+	SmtpPassword string
+	LogPath      string
+}
+
+func NewMailer(config Config) (Mailer, error) {
+	switch config.Driver {
+	case "smtp":
+		return NewSmtpMailer(config), nil
+	default:
+		if config.LogPath == "" {
+			return NewLogMailer(os.Stdout), nil
+		}
+		return NewFileMailer(config.LogPath)
+	}
+}
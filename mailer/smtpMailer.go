@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type SmtpMailer struct {
+	from     string
+	address  string
+	host     string
+	username string
+	password string
+}
+
+func NewSmtpMailer(config Config) *SmtpMailer {
+	return &SmtpMailer{
+		from:     config.From,
+		address:  net.JoinHostPort(config.SmtpHost, config.SmtpPort),
+		host:     config.SmtpHost,
+		username: config.SmtpUsername,
+		password: config.SmtpPassword,
+	}
+}
+
+func (m *SmtpMailer) Send(message Message) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	return smtp.SendMail(m.address, auth, m.from, []string{message.To}, m.buildMessage(message))
+}
+
+func (m *SmtpMailer) buildMessage(message Message) []byte {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "From: %s\r\n", m.from)
+	fmt.Fprintf(&builder, "To: %s\r\n", message.To)
+	fmt.Fprintf(&builder, "Subject: %s\r\n", message.Subject)
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(message.Body)
+
+	return []byte(builder.String())
+}
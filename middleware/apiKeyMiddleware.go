@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ApiKeyMiddleware struct {
+	database *database.Database
+}
+
+func NewApiKeyMiddleware(database *database.Database) *ApiKeyMiddleware {
+	return &ApiKeyMiddleware{
+		database: database,
+	}
+}
+
+func (m *ApiKeyMiddleware) AllowScope(scope string, fallback ...echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		fallbackHandler := next
+		for i := len(fallback) - 1; i >= 0; i-- {
+			fallbackHandler = fallback[i](fallbackHandler)
+		}
+
+		return func(c echo.Context) error {
+			authorization := c.Request().Header.Get(echo.HeaderAuthorization)
+			if !strings.HasPrefix(authorization, "ApiKey ") {
+				return fallbackHandler(c)
+			}
+
+			apiKey := model.StoreApiKey{
+				KeyHash: helper.HashToken(strings.TrimSpace(strings.TrimPrefix(authorization, "ApiKey "))),
+			}
+			if err := apiKey.GetByKeyHash(m.database.Conn); err != nil || apiKey.IsRevoked() {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid API key")
+			}
+
+			if !apiKey.HasScope(scope) {
+				return echo.NewHTTPError(http.StatusForbidden, "API key is missing the "+scope+" scope")
+			}
+
+			store := model.Store{
+				ID: apiKey.StoreID,
+			}
+			if err := store.GetByID(m.database.Conn); err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid API key")
+			}
+
+			owner := model.User{
+				Email: store.OwnerEmail,
+			}
+			if err := owner.GetByEmail(m.database.Conn); err != nil || owner.DeletedAt != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid API key")
+			}
+
+			if owner.IsBanned() {
+				return echo.NewHTTPError(http.StatusForbidden, "The store owner has been banned")
+			}
+
+			if err := apiKey.Touch(m.database.Conn); err != nil {
+				log.Println(err)
+			}
+
+			helper.SetPrincipal(c, helper.Principal{
+				Email:    store.OwnerEmail,
+				StoreID:  store.ID,
+				ApiKeyID: apiKey.ID,
+				Scopes:   apiKey.Scopes,
+			})
+
+			return next(c)
+		}
+	}
+}
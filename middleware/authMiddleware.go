@@ -1,18 +1,129 @@
 package middleware
 
 import (
+	"ecommerce-api/database"
+	"ecommerce-api/denylist"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 )
 
 type AuthMiddleware struct {
-	config    echojwt.Config
-	LoginOnly echo.MiddlewareFunc
+	config        echojwt.Config
+	database      *database.Database
+	denylist      *denylist.Denylist
+	LoginOnly     echo.MiddlewareFunc
+	LoginOptional echo.MiddlewareFunc
+	AdminOnly     echo.MiddlewareFunc
+	SellerOnly    echo.MiddlewareFunc
+	BuyerOnly     echo.MiddlewareFunc
 }
 
-func NewAuthMiddleware(config echojwt.Config) *AuthMiddleware {
-	return &AuthMiddleware{
-		config:    config,
-		LoginOnly: echojwt.JWT(config.SigningKey.([]byte)),
+func NewAuthMiddleware(config echojwt.Config, database *database.Database, denylist *denylist.Denylist) *AuthMiddleware {
+	m := &AuthMiddleware{
+		config:     config,
+		database:   database,
+		denylist:   denylist,
+		AdminOnly:  RequireRole(model.RoleAdmin),
+		SellerOnly: RequireRole(model.RoleSeller),
+		BuyerOnly:  RequireRole(model.RoleBuyer),
+	}
+
+	jwtMiddleware := echojwt.WithConfig(echojwt.Config{
+		KeyFunc:       config.KeyFunc,
+		NewClaimsFunc: config.NewClaimsFunc,
+	})
+	m.LoginOnly = func(next echo.HandlerFunc) echo.HandlerFunc {
+		return jwtMiddleware(m.resolvePrincipal(next))
+	}
+	m.LoginOptional = func(next echo.HandlerFunc) echo.HandlerFunc {
+		loginOnly := m.LoginOnly(next)
+		return func(c echo.Context) error {
+			if c.Request().Header.Get(echo.HeaderAuthorization) == "" {
+				return next(c)
+			}
+			return loginOnly(c)
+		}
+	}
+
+	return m
+}
+
+func (m *AuthMiddleware) resolvePrincipal(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token, ok := c.Get("user").(*jwt.Token)
+		if !ok {
+			return echo.ErrUnauthorized
+		}
+
+		claims, ok := token.Claims.(*helper.JwtCustomClaims)
+		if !ok || claims.Email == "" {
+			return echo.ErrUnauthorized
+		}
+
+		revoked, err := m.denylist.IsRevoked(claims.ID, claims.SessionID)
+		if err != nil {
+			return echo.ErrInternalServerError
+		}
+		if revoked {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Token has been revoked")
+		}
+
+		user := model.User{
+			Email: claims.Email,
+		}
+		if err := user.GetByEmail(m.database.Conn); err != nil {
+			if err.Error() == "sql: no rows in result set" {
+				return echo.ErrUnauthorized
+			}
+			return echo.ErrInternalServerError
+		}
+
+		if user.IsBanned() {
+			return echo.NewHTTPError(http.StatusForbidden, "Your account has been banned")
+		}
+
+		principal := helper.Principal{
+			Email:     user.Email,
+			Roles:     user.Roles,
+			TokenID:   claims.ID,
+			SessionID: claims.SessionID,
+		}
+
+		if principal.HasRole(model.RoleSeller) {
+			store := model.Store{
+				OwnerEmail: principal.Email,
+			}
+			if err := store.GetByOwnerEmail(m.database.Conn); err == nil {
+				principal.StoreID = store.ID
+			} else if err.Error() != "sql: no rows in result set" {
+				return echo.ErrInternalServerError
+			}
+		}
+
+		helper.SetPrincipal(c, principal)
+
+		return next(c)
+	}
+}
+
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, err := helper.CurrentPrincipal(c)
+			if err != nil {
+				return echo.ErrUnauthorized
+			}
+
+			if !principal.HasRole(roles...) {
+				return echo.NewHTTPError(http.StatusForbidden, "You don't have permission to access this resource")
+			}
+
+			return next(c)
+		}
 	}
 }
@@ -0,0 +1,316 @@
+package model
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	AddressTypeShipping = "shipping"
+	AddressTypeBilling  = "billing"
+)
+
+type Address struct {
+	ID            string     `json:"id,omitempty"`
+	UserEmail     string     `json:"user_email,omitempty"`
+	Type          string     `json:"type,omitempty"`
+	RecipientName string     `json:"recipient_name,omitempty"`
+	Line1         string     `json:"line1,omitempty"`
+	Line2         string     `json:"line2,omitempty"`
+	City          string     `json:"city,omitempty"`
+	Region        string     `json:"region,omitempty"`
+	PostalCode    string     `json:"postal_code,omitempty"`
+	Country       string     `json:"country,omitempty"`
+	IsDefault     bool       `json:"is_default"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+func (a *Address) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&a.ID,
+		&a.UserEmail,
+		&a.Type,
+		&a.RecipientName,
+		&a.Line1,
+		&a.Line2,
+		&a.City,
+		&a.Region,
+		&a.PostalCode,
+		&a.Country,
+		&a.IsDefault,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+}
+
+func scanRowsAddress(rows *sql.Rows) ([]Address, error) {
+	var addresses []Address
+
+	for rows.Next() {
+		var address Address
+
+		if err := rows.Scan(
+			&address.ID,
+			&address.UserEmail,
+			&address.Type,
+			&address.RecipientName,
+			&address.Line1,
+			&address.Line2,
+			&address.City,
+			&address.Region,
+			&address.PostalCode,
+			&address.Country,
+			&address.IsDefault,
+			&address.CreatedAt,
+			&address.UpdatedAt,
+		); err != nil {
+			return addresses, err
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+type AddressSave struct {
+	Type          string `json:"type" validate:"required,oneof=shipping billing"`
+	RecipientName string `json:"recipient_name" validate:"required,max=255"`
+	Line1         string `json:"line1" validate:"required,max=255"`
+	Line2         string `json:"line2" validate:"max=255"`
+	City          string `json:"city" validate:"required,max=255"`
+	Region        string `json:"region" validate:"max=255"`
+	PostalCode    string `json:"postal_code" validate:"required,max=16"`
+	Country       string `json:"country" validate:"required,iso3166_1_alpha2"`
+	IsDefault     bool   `json:"is_default"`
+}
+
+func (a *AddressSave) ToAddress() Address {
+	return Address{
+		Type:          a.Type,
+		RecipientName: a.RecipientName,
+		Line1:         a.Line1,
+		Line2:         a.Line2,
+		City:          a.City,
+		Region:        a.Region,
+		PostalCode:    a.PostalCode,
+		Country:       a.Country,
+		IsDefault:     a.IsDefault,
+	}
+}
+
+type AddressSnapshot struct {
+	ID            string `json:"id,omitempty"`
+	Type          string `json:"type,omitempty"`
+	RecipientName string `json:"recipient_name,omitempty"`
+	Line1         string `json:"line1,omitempty"`
+	Line2         string `json:"line2,omitempty"`
+	City          string `json:"city,omitempty"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code,omitempty"`
+	Country       string `json:"country,omitempty"`
+}
+
+func (a *Address) ToSnapshot() *AddressSnapshot {
+	return &AddressSnapshot{
+		ID:            a.ID,
+		Type:          a.Type,
+		RecipientName: a.RecipientName,
+		Line1:         a.Line1,
+		Line2:         a.Line2,
+		City:          a.City,
+		Region:        a.Region,
+		PostalCode:    a.PostalCode,
+		Country:       a.Country,
+	}
+}
+
+func (a *AddressSnapshot) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func (a *AddressSnapshot) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return errors.New("address snapshot must be scanned from bytes")
+	}
+
+	return json.Unmarshal(data, a)
+}
+
+func (a *Address) Create(dbConn DBConn) error {
+	sql := `INSERT INTO addresses (user_email, type, recipient_name, line1, line2, city, region, postal_code, country, is_default)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING id, user_email, type, recipient_name, line1, line2, city, region, postal_code, country, is_default, created_at, updated_at`
+
+	return a.scanRow(dbConn.QueryRow(
+		sql,
+		a.UserEmail,
+		a.Type,
+		a.RecipientName,
+		a.Line1,
+		a.Line2,
+		a.City,
+		a.Region,
+		a.PostalCode,
+		a.Country,
+		a.IsDefault,
+	))
+}
+
+func (a *Address) UpdateByIDAndUserEmail(dbConn DBConn) error {
+	sql := `UPDATE addresses SET type = $1, recipient_name = $2, line1 = $3, line2 = $4, city = $5,
+	region = $6, postal_code = $7, country = $8, is_default = $9
+	WHERE id = $10 AND user_email = $11
+	RETURNING id, user_email, type, recipient_name, line1, line2, city, region, postal_code, country, is_default, created_at, updated_at`
+
+	return a.scanRow(dbConn.QueryRow(
+		sql,
+		a.Type,
+		a.RecipientName,
+		a.Line1,
+		a.Line2,
+		a.City,
+		a.Region,
+		a.PostalCode,
+		a.Country,
+		a.IsDefault,
+		a.ID,
+		a.UserEmail,
+	))
+}
+
+func (a *Address) GetByIDAndUserEmail(dbConn DBConn) error {
+	sql := `SELECT id, user_email, type, recipient_name, line1, line2, city, region, postal_code, country, is_default, created_at, updated_at
+	FROM addresses
+	WHERE id = $1 AND user_email = $2`
+
+	return a.scanRow(dbConn.QueryRow(
+		sql,
+		a.ID,
+		a.UserEmail,
+	))
+}
+
+func (a *Address) GetDefaultByUserEmailAndType(dbConn DBConn) error {
+	sql := `SELECT id, user_email, type, recipient_name, line1, line2, city, region, postal_code, country, is_default, created_at, updated_at
+	FROM addresses
+	WHERE user_email = $1 AND type = $2 AND is_default`
+
+	return a.scanRow(dbConn.QueryRow(
+		sql,
+		a.UserEmail,
+		a.Type,
+	))
+}
+
+func (a *Address) DeleteByIDAndUserEmail(dbConn DBConn) error {
+	sql := `DELETE FROM addresses
+	WHERE id = $1 AND user_email = $2
+	RETURNING id, user_email, type, recipient_name, line1, line2, city, region, postal_code, country, is_default, created_at, updated_at`
+
+	return a.scanRow(dbConn.QueryRow(
+		sql,
+		a.ID,
+		a.UserEmail,
+	))
+}
+
+func GetAllAddressByUserEmail(dbConn DBConn, email string) ([]Address, error) {
+	sql := `SELECT id, user_email, type, recipient_name, line1, line2, city, region, postal_code, country, is_default, created_at, updated_at
+	FROM addresses
+	WHERE user_email = $1
+	ORDER BY is_default DESC, created_at DESC`
+
+	rows, err := dbConn.Query(sql, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsAddress(rows)
+}
+
+func CountAddressByUserEmailAndType(dbConn DBConn, email string, addressType string) (int, error) {
+	sql := `SELECT COUNT(*)
+	FROM addresses
+	WHERE user_email = $1 AND type = $2`
+
+	var count int
+	if err := dbConn.QueryRow(sql, email, addressType).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func ClearDefaultAddressByUserEmailAndType(dbConn DBConn, email string, addressType string) error {
+	sql := `UPDATE addresses SET is_default = FALSE
+	WHERE user_email = $1 AND type = $2 AND is_default`
+
+	if _, err := dbConn.Exec(
+		sql,
+		email,
+		addressType,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func EnsureDefaultAddressByUserEmailAndType(dbConn DBConn, email string, addressType string, demotedID string) error {
+	sql := `UPDATE addresses SET is_default = TRUE
+	WHERE id = (
+		SELECT id FROM addresses
+		WHERE user_email = $1 AND type = $2
+		ORDER BY id = $3, created_at DESC
+		LIMIT 1
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM addresses
+		WHERE user_email = $1 AND type = $2 AND is_default
+	)`
+
+	if _, err := dbConn.Exec(
+		sql,
+		email,
+		addressType,
+		demotedID,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeleteAllAddressByUserEmail(dbConn DBConn, email string) error {
+	sql := `DELETE FROM addresses
+	WHERE user_email = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+type Avatar struct {
+	Key      string            `json:"key,omitempty"`
+	URL      string            `json:"url,omitempty"`
+	Variants map[string]string `json:"variants,omitempty"`
+}
+
+func (a *Avatar) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func (a *Avatar) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return errors.New("avatar must be scanned from bytes")
+	}
+
+	return json.Unmarshal(data, a)
+}
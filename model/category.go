@@ -0,0 +1,150 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Category struct {
+	ID        string     `json:"id,omitempty"`
+	ParentID  *string    `json:"parent_id"`
+	Name      string     `json:"name,omitempty"`
+	Children  []Category `json:"children,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+func (c *Category) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&c.ID,
+		&c.ParentID,
+		&c.Name,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+}
+
+func scanRowsCategory(rows *sql.Rows) ([]Category, error) {
+	var categories []Category
+
+	for rows.Next() {
+		var category Category
+
+		if err := rows.Scan(
+			&category.ID,
+			&category.ParentID,
+			&category.Name,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+		); err != nil {
+			return categories, err
+		}
+
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+type CategorySave struct {
+	Name     string `json:"name" validate:"required,max=255"`
+	ParentID string `json:"parent_id" validate:"omitempty,uuid"`
+}
+
+func (c *CategorySave) ToCategory() Category {
+	category := Category{
+		Name: c.Name,
+	}
+	if c.ParentID != "" {
+		category.ParentID = &c.ParentID
+	}
+
+	return category
+}
+
+func (c *Category) Create(dbConn DBConn) error {
+	sql := `INSERT INTO categories (parent_id, name)
+	VALUES ($1, $2)
+	RETURNING id, parent_id, name, created_at, updated_at`
+
+	return c.scanRow(dbConn.QueryRow(
+		sql,
+		c.ParentID,
+		c.Name,
+	))
+}
+
+func (c *Category) UpdateByID(dbConn DBConn) error {
+	sql := `UPDATE categories SET parent_id = $1, name = $2
+	WHERE id = $3
+	RETURNING id, parent_id, name, created_at, updated_at`
+
+	return c.scanRow(dbConn.QueryRow(
+		sql,
+		c.ParentID,
+		c.Name,
+		c.ID,
+	))
+}
+
+func (c *Category) GetByID(dbConn DBConn) error {
+	sql := `SELECT id, parent_id, name, created_at, updated_at
+	FROM categories
+	WHERE id = $1`
+
+	return c.scanRow(dbConn.QueryRow(
+		sql,
+		c.ID,
+	))
+}
+
+func (c *Category) Delete(dbConn DBConn) error {
+	sql := `DELETE FROM categories
+	WHERE id = $1
+	RETURNING id, parent_id, name, created_at, updated_at`
+
+	return c.scanRow(dbConn.QueryRow(
+		sql,
+		c.ID,
+	))
+}
+
+func GetAllCategory(dbConn DBConn) ([]Category, error) {
+	sql := `SELECT id, parent_id, name, created_at, updated_at
+	FROM categories
+	ORDER BY name, id`
+
+	rows, err := dbConn.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsCategory(rows)
+}
+
+func LockCategoryTree(dbConn DBConn) error {
+	sql := `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`
+
+	if _, err := dbConn.Exec(sql); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func IsCategoryDescendant(dbConn DBConn, ancestorID string, categoryID string) (bool, error) {
+	sql := `WITH RECURSIVE tree AS (
+		SELECT id FROM categories WHERE id = $1
+		UNION ALL
+		SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id
+	)
+	SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)`
+
+	var descendant bool
+	if err := dbConn.QueryRow(sql, ancestorID, categoryID).Scan(&descendant); err != nil {
+		return false, err
+	}
+
+	return descendant, nil
+}
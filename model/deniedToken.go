@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type DeniedToken struct {
+	Key       string
+	ExpiresAt *time.Time
+	CreatedAt *time.Time
+}
+
+func (d *DeniedToken) Create(dbConn DBConn) error {
+	sql := `INSERT INTO denied_tokens (key, expires_at)
+	VALUES ($1, $2)
+	ON CONFLICT (key) DO UPDATE SET expires_at = GREATEST(denied_tokens.expires_at, EXCLUDED.expires_at)
+	RETURNING key, expires_at, created_at`
+
+	return dbConn.QueryRow(
+		sql,
+		d.Key,
+		d.ExpiresAt,
+	).Scan(
+		&d.Key,
+		&d.ExpiresAt,
+		&d.CreatedAt,
+	)
+}
+
+func ExistsDeniedToken(dbConn DBConn, keys []string, now time.Time) (bool, error) {
+	sql := `SELECT EXISTS (
+		SELECT 1 FROM denied_tokens
+		WHERE key = ANY($1) AND expires_at > $2
+	)`
+
+	var exists bool
+	if err := dbConn.QueryRow(
+		sql,
+		pq.Array(keys),
+		now,
+	).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func DeleteExpiredDeniedTokens(dbConn DBConn, now time.Time) error {
+	sql := `DELETE FROM denied_tokens
+	WHERE expires_at <= $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		now,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type EmailVerificationToken struct {
+	TokenHash string
+	UserEmail string
+	ExpiresAt *time.Time
+	UsedAt    *time.Time
+	CreatedAt *time.Time
+	Expired   bool
+}
+
+func (e *EmailVerificationToken) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&e.TokenHash,
+		&e.UserEmail,
+		&e.ExpiresAt,
+		&e.UsedAt,
+		&e.CreatedAt,
+		&e.Expired,
+	)
+}
+
+func (e *EmailVerificationToken) IsExpired() bool {
+	return e.Expired
+}
+
+func (e *EmailVerificationToken) IsUsed() bool {
+	return e.UsedAt != nil
+}
+
+type EmailVerify struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (e *EmailVerificationToken) Create(dbConn DBConn) error {
+	sql := `INSERT INTO email_verification_tokens (token_hash, user_email)
+	VALUES ($1, $2)
+	RETURNING token_hash, user_email, expires_at, used_at, created_at, expires_at < CURRENT_TIMESTAMP`
+
+	return e.scanRow(dbConn.QueryRow(
+		sql,
+		e.TokenHash,
+		e.UserEmail,
+	))
+}
+
+func (e *EmailVerificationToken) GetByTokenHashForUpdate(dbConn DBConn) error {
+	sql := `SELECT token_hash, user_email, expires_at, used_at, created_at, expires_at < CURRENT_TIMESTAMP
+	FROM email_verification_tokens
+	WHERE token_hash = $1
+	FOR UPDATE`
+
+	return e.scanRow(dbConn.QueryRow(
+		sql,
+		e.TokenHash,
+	))
+}
+
+func (e *EmailVerificationToken) MarkUsed(dbConn DBConn) error {
+	sql := `UPDATE email_verification_tokens SET used_at = CURRENT_TIMESTAMP
+	WHERE token_hash = $1
+	RETURNING token_hash, user_email, expires_at, used_at, created_at, expires_at < CURRENT_TIMESTAMP`
+
+	return e.scanRow(dbConn.QueryRow(
+		sql,
+		e.TokenHash,
+	))
+}
+
+func InvalidateEmailVerificationTokensByUserEmail(dbConn DBConn, email string) error {
+	sql := `UPDATE email_verification_tokens SET used_at = CURRENT_TIMESTAMP
+	WHERE user_email = $1 AND used_at IS NULL`
+
+	if _, err := dbConn.Exec(
+		sql,
+		email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
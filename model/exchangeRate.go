@@ -0,0 +1,131 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ExchangeRate struct {
+	BaseCurrency  string     `json:"base_currency,omitempty"`
+	QuoteCurrency string     `json:"quote_currency,omitempty"`
+	Rate          string     `json:"rate,omitempty"`
+	UpdatedBy     string     `json:"updated_by,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+func (e *ExchangeRate) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&e.BaseCurrency,
+		&e.QuoteCurrency,
+		&e.Rate,
+		&e.UpdatedBy,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+}
+
+func scanRowsExchangeRate(rows *sql.Rows) ([]ExchangeRate, error) {
+	var rates []ExchangeRate
+
+	for rows.Next() {
+		var rate ExchangeRate
+
+		if err := rows.Scan(
+			&rate.BaseCurrency,
+			&rate.QuoteCurrency,
+			&rate.Rate,
+			&rate.UpdatedBy,
+			&rate.CreatedAt,
+			&rate.UpdatedAt,
+		); err != nil {
+			return rates, err
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+type ExchangeRateUpdate struct {
+	BaseCurrency  string `json:"base_currency" validate:"required,iso4217"`
+	QuoteCurrency string `json:"quote_currency" validate:"required,iso4217,nefield=BaseCurrency"`
+	Rate          string `json:"rate" validate:"required,numeric"`
+}
+
+func (e *ExchangeRateUpdate) ToExchangeRate() ExchangeRate {
+	return ExchangeRate{
+		BaseCurrency:  e.BaseCurrency,
+		QuoteCurrency: e.QuoteCurrency,
+		Rate:          e.Rate,
+	}
+}
+
+func (e *ExchangeRate) Upsert(dbConn DBConn) error {
+	sql := `INSERT INTO exchange_rates (base_currency, quote_currency, rate, updated_by)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (base_currency, quote_currency) DO UPDATE SET rate = EXCLUDED.rate, updated_by = EXCLUDED.updated_by
+	RETURNING base_currency, quote_currency, rate, updated_by, created_at, updated_at`
+
+	return e.scanRow(dbConn.QueryRow(
+		sql,
+		e.BaseCurrency,
+		e.QuoteCurrency,
+		e.Rate,
+		e.UpdatedBy,
+	))
+}
+
+func (e *ExchangeRate) GetByCurrencies(dbConn DBConn) error {
+	sql := `SELECT base_currency, quote_currency, rate, updated_by, created_at, updated_at
+	FROM exchange_rates
+	WHERE base_currency = $1 AND quote_currency = $2`
+
+	return e.scanRow(dbConn.QueryRow(
+		sql,
+		e.BaseCurrency,
+		e.QuoteCurrency,
+	))
+}
+
+func (e *ExchangeRate) Delete(dbConn DBConn) error {
+	sql := `DELETE FROM exchange_rates
+	WHERE base_currency = $1 AND quote_currency = $2
+	RETURNING base_currency, quote_currency, rate, updated_by, created_at, updated_at`
+
+	return e.scanRow(dbConn.QueryRow(
+		sql,
+		e.BaseCurrency,
+		e.QuoteCurrency,
+	))
+}
+
+func GetAllExchangeRate(dbConn DBConn) ([]ExchangeRate, error) {
+	sql := `SELECT base_currency, quote_currency, rate, updated_by, created_at, updated_at
+	FROM exchange_rates
+	ORDER BY base_currency, quote_currency`
+
+	rows, err := dbConn.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsExchangeRate(rows)
+}
+
+func AnonymizeExchangeRateUpdatedBy(dbConn DBConn, email string, anonymousEmail string) error {
+	sql := `UPDATE exchange_rates SET updated_by = $1
+	WHERE updated_by = $2`
+
+	if _, err := dbConn.Exec(
+		sql,
+		anonymousEmail,
+		email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
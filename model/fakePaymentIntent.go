@@ -0,0 +1,67 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type FakePaymentIntent struct {
+	Reference string
+	TopUpID   string
+	UserEmail string
+	Amount    int64
+	Currency  string
+	Status    string
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+func (f *FakePaymentIntent) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&f.Reference,
+		&f.TopUpID,
+		&f.UserEmail,
+		&f.Amount,
+		&f.Currency,
+		&f.Status,
+		&f.CreatedAt,
+		&f.UpdatedAt,
+	)
+}
+
+func (f *FakePaymentIntent) CreateOrGetByTopUpID(dbConn DBConn) error {
+	sql := `WITH inserted AS (
+		INSERT INTO fake_payment_intents (reference, top_up_id, user_email, amount, currency)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (top_up_id) DO NOTHING
+		RETURNING reference, top_up_id, user_email, amount, currency, status, created_at, updated_at
+	)
+	SELECT reference, top_up_id, user_email, amount, currency, status, created_at, updated_at FROM inserted
+	UNION ALL
+	SELECT reference, top_up_id, user_email, amount, currency, status, created_at, updated_at FROM fake_payment_intents WHERE top_up_id = $2
+	LIMIT 1`
+
+	return f.scanRow(dbConn.QueryRow(
+		sql,
+		f.Reference,
+		f.TopUpID,
+		f.UserEmail,
+		f.Amount,
+		f.Currency,
+	))
+}
+
+func (f *FakePaymentIntent) GetByReference(dbConn DBConn) error {
+	sql := `SELECT reference, top_up_id, user_email, amount, currency, status, created_at, updated_at
+	FROM fake_payment_intents WHERE reference = $1`
+
+	return f.scanRow(dbConn.QueryRow(sql, f.Reference))
+}
+
+func (f *FakePaymentIntent) Settle(dbConn DBConn, status string) error {
+	sql := `UPDATE fake_payment_intents SET status = $1
+	WHERE reference = $2 AND status = 'pending'
+	RETURNING reference, top_up_id, user_email, amount, currency, status, created_at, updated_at`
+
+	return f.scanRow(dbConn.QueryRow(sql, status, f.Reference))
+}
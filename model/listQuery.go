@@ -0,0 +1,205 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("Invalid cursor")
+	ErrInvalidSort   = errors.New("Invalid sort")
+)
+
+type ListQuery struct {
+	Limit  int
+	Sort   string
+	Cursor string
+}
+
+type ListPage[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type listFilter struct {
+	condition string
+	args      []interface{}
+}
+
+type listSortField[T any] struct {
+	column string
+	cast   string
+	value  func(item T) string
+}
+
+type listSpec[T any] struct {
+	sorts       map[string]listSortField[T]
+	defaultSort string
+	keyColumn   string
+	keyCast     string
+	key         func(item T) string
+}
+
+type listCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	Key   string `json:"k"`
+}
+
+func (c listCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListCursor(cursor string) (listCursor, error) {
+	var decoded listCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return decoded, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return decoded, ErrInvalidCursor
+	}
+
+	return decoded, nil
+}
+
+func runListQuery[T any](
+	dbConn DBConn,
+	spec listSpec[T],
+	selectSql string,
+	filters []listFilter,
+	query ListQuery,
+	scan func(rows *sql.Rows) ([]T, error),
+) (ListPage[T], error) {
+	page := ListPage[T]{
+		Items: []T{},
+	}
+
+	sortName := query.Sort
+	if sortName == "" {
+		sortName = spec.defaultSort
+	}
+
+	descending := strings.HasPrefix(sortName, "-")
+	sortField, ok := spec.sorts[strings.TrimPrefix(sortName, "-")]
+	if !ok {
+		return page, ErrInvalidSort
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, filter := range filters {
+		condition := filter.condition
+		for _, arg := range filter.args {
+			args = append(args, arg)
+			condition = strings.Replace(condition, "?", fmt.Sprintf("$%d", len(args)), 1)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeListCursor(query.Cursor)
+		if err != nil {
+			return page, err
+		}
+
+		if cursor.Sort != sortName {
+			return page, ErrInvalidCursor
+		}
+
+		operator := ">"
+		if descending {
+			operator = "<"
+		}
+
+		args = append(args, cursor.Value, cursor.Key)
+		conditions = append(conditions, fmt.Sprintf(
+			"(%s, %s) %s ($%d::%s, $%d::%s)",
+			sortField.column,
+			spec.keyColumn,
+			operator,
+			len(args)-1,
+			sortField.cast,
+			len(args),
+			spec.keyCast,
+		))
+	}
+
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+
+	sql := selectSql
+	if len(conditions) > 0 {
+		sql += "\n\tWHERE " + strings.Join(conditions, " AND ")
+	}
+	sql += fmt.Sprintf(
+		"\n\tORDER BY %s %s, %s %s\n\tLIMIT %d",
+		sortField.column,
+		direction,
+		spec.keyColumn,
+		direction,
+		limit+1,
+	)
+
+	rows, err := dbConn.Query(sql, args...)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && query.Cursor != "" && err.Code.Class() == "22" {
+			return page, ErrInvalidCursor
+		}
+		return page, err
+	}
+	defer rows.Close()
+
+	items, err := scan(rows)
+	if err != nil {
+		return page, err
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[len(items)-1]
+		page.NextCursor = listCursor{
+			Sort:  sortName,
+			Value: sortField.value(last),
+			Key:   spec.key(last),
+		}.encode()
+	}
+
+	if items != nil {
+		page.Items = items
+	}
+
+	return page, nil
+}
+
+func timeListValue(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+var errQueryRecorded = errors.New("query recorded")
+
+type recordingDBConn struct {
+	sql   string
+	args  []any
+	err   error
+	calls int
+}
+
+func (r *recordingDBConn) QueryRow(query string, args ...any) *sql.Row {
+	panic("unexpected QueryRow")
+}
+
+func (r *recordingDBConn) Query(query string, args ...any) (*sql.Rows, error) {
+	r.sql = query
+	r.args = args
+	r.calls++
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	return nil, errQueryRecorded
+}
+
+func (r *recordingDBConn) Exec(query string, args ...any) (sql.Result, error) {
+	panic("unexpected Exec")
+}
+
+func TestListCursorRoundTrip(t *testing.T) {
+	cursor := listCursor{
+		Sort:  "-price",
+		Value: "10000",
+		Key:   "550e8400-e29b-41d4-a716-446655440000",
+	}
+
+	decoded, err := decodeListCursor(cursor.encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded != cursor {
+		t.Fatalf("decoded = %+v, want %+v", decoded, cursor)
+	}
+}
+
+func TestDecodeListCursorRejectsMalformedCursors(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24", "W10"} {
+		if _, err := decodeListCursor(cursor); err != ErrInvalidCursor {
+			t.Errorf("decodeListCursor(%q) error = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
+
+func TestRunListQueryRejectsBadSortAndCursor(t *testing.T) {
+	tests := []struct {
+		name  string
+		query ListQuery
+		want  error
+	}{
+		{"unknown sort", ListQuery{Sort: "password"}, ErrInvalidSort},
+		{"malformed cursor", ListQuery{Cursor: "not base64!"}, ErrInvalidCursor},
+		{"cursor from another sort", ListQuery{Sort: "name", Cursor: listCursor{Sort: "-created_at", Value: "x", Key: "y"}.encode()}, ErrInvalidCursor},
+		{"cursor from the reversed default sort", ListQuery{Cursor: listCursor{Sort: "created_at", Value: "x", Key: "y"}.encode()}, ErrInvalidCursor},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dbConn := &recordingDBConn{}
+
+			page, err := runListQuery(dbConn, productListSpec, "SELECT * FROM products", nil, test.query, scanRowsProduct)
+			if err != test.want {
+				t.Fatalf("error = %v, want %v", err, test.want)
+			}
+			if dbConn.calls != 0 {
+				t.Fatalf("query ran %d times, want 0", dbConn.calls)
+			}
+			if page.Items == nil {
+				t.Fatal("items is nil, want an empty list")
+			}
+		})
+	}
+}
+
+func TestRunListQueryBuildsKeysetCondition(t *testing.T) {
+	key := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name      string
+		query     ListQuery
+		condition string
+		order     string
+		limit     string
+	}{
+		{
+			"ascending",
+			ListQuery{Sort: "price", Limit: 5, Cursor: listCursor{Sort: "price", Value: "10000", Key: key}.encode()},
+			"(price, id) > ($2::BIGINT, $3::UUID)",
+			"ORDER BY price ASC, id ASC",
+			"LIMIT 6",
+		},
+		{
+			"descending",
+			ListQuery{Sort: "-price", Limit: 500, Cursor: listCursor{Sort: "-price", Value: "10000", Key: key}.encode()},
+			"(price, id) < ($2::BIGINT, $3::UUID)",
+			"ORDER BY price DESC, id DESC",
+			"LIMIT 101",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dbConn := &recordingDBConn{}
+			filter := ProductFilter{Currency: "USD"}
+
+			_, err := runListQuery(dbConn, productListSpec, "SELECT * FROM products", filter.listFilters(), test.query, scanRowsProduct)
+			if err != errQueryRecorded {
+				t.Fatalf("error = %v, want the recorded query", err)
+			}
+
+			for _, part := range []string{"WHERE currency = $1 AND " + test.condition, test.order, test.limit} {
+				if !strings.Contains(dbConn.sql, part) {
+					t.Errorf("sql %q does not contain %q", dbConn.sql, part)
+				}
+			}
+
+			if len(dbConn.args) != 3 || dbConn.args[0] != "USD" || dbConn.args[1] != "10000" || dbConn.args[2] != key {
+				t.Errorf("args = %v, want [USD 10000 %s]", dbConn.args, key)
+			}
+		})
+	}
+}
+
+func TestRunListQueryMapsCursorCastErrorsToInvalidCursor(t *testing.T) {
+	cursor := listCursor{Sort: "price", Value: "not a number", Key: "not a uuid"}.encode()
+
+	dbConn := &recordingDBConn{err: &pq.Error{Code: "22P02"}}
+	if _, err := runListQuery(dbConn, productListSpec, "SELECT * FROM products", nil, ListQuery{Sort: "price", Cursor: cursor}, scanRowsProduct); err != ErrInvalidCursor {
+		t.Fatalf("error = %v, want ErrInvalidCursor", err)
+	}
+
+	dbConn = &recordingDBConn{err: &pq.Error{Code: "22P02"}}
+	if _, err := runListQuery(dbConn, productListSpec, "SELECT * FROM products", nil, ListQuery{Sort: "price"}, scanRowsProduct); err == ErrInvalidCursor {
+		t.Fatal("error = ErrInvalidCursor without a cursor")
+	}
+}
@@ -0,0 +1,187 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	BlockedUntil  *time.Time
+	LastFailureAt *time.Time
+}
+
+func (l *LoginAttempt) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&l.Key,
+		&l.Failures,
+		&l.BlockedUntil,
+		&l.LastFailureAt,
+	)
+}
+
+func (l *LoginAttempt) GetByKey(dbConn DBConn) error {
+	sql := `SELECT key, failures, blocked_until, last_failure_at
+	FROM login_attempts
+	WHERE key = $1`
+
+	return l.scanRow(dbConn.QueryRow(
+		sql,
+		l.Key,
+	))
+}
+
+func (l *LoginAttempt) RecordFailure(dbConn DBConn, now time.Time, windowStart time.Time) error {
+	sql := `INSERT INTO login_attempts (key, failures, last_failure_at)
+	VALUES ($1, 1, $2)
+	ON CONFLICT (key) DO UPDATE SET
+		failures = CASE WHEN login_attempts.last_failure_at < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+		last_failure_at = EXCLUDED.last_failure_at
+	RETURNING key, failures, blocked_until, last_failure_at`
+
+	return l.scanRow(dbConn.QueryRow(
+		sql,
+		l.Key,
+		now,
+		windowStart,
+	))
+}
+
+func (l *LoginAttempt) Block(dbConn DBConn) error {
+	sql := `UPDATE login_attempts SET blocked_until = $1
+	WHERE key = $2`
+
+	if _, err := dbConn.Exec(
+		sql,
+		l.BlockedUntil,
+		l.Key,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (l *LoginAttempt) Delete(dbConn DBConn) error {
+	sql := `DELETE FROM login_attempts
+	WHERE key = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		l.Key,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type LoginLockout struct {
+	ID          string     `json:"id,omitempty"`
+	Key         string     `json:"key,omitempty"`
+	Failures    int        `json:"failures"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+	UnlockedBy  *string    `json:"unlocked_by,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+}
+
+func (l *LoginLockout) Create(dbConn DBConn) error {
+	sql := `INSERT INTO login_lockouts (key, failures, locked_until)
+	VALUES ($1, $2, $3)
+	RETURNING id, key, failures, locked_until, unlocked_at, unlocked_by, created_at`
+
+	return dbConn.QueryRow(
+		sql,
+		l.Key,
+		l.Failures,
+		l.LockedUntil,
+	).Scan(
+		&l.ID,
+		&l.Key,
+		&l.Failures,
+		&l.LockedUntil,
+		&l.UnlockedAt,
+		&l.UnlockedBy,
+		&l.CreatedAt,
+	)
+}
+
+func GetAllActiveLoginLockout(dbConn DBConn) ([]LoginLockout, error) {
+	var lockouts []LoginLockout
+	sql := `SELECT id, key, failures, locked_until, unlocked_at, unlocked_by, created_at
+	FROM login_lockouts
+	WHERE unlocked_at IS NULL AND locked_until > CURRENT_TIMESTAMP
+	ORDER BY created_at DESC`
+
+	rows, err := dbConn.Query(sql)
+	if err != nil {
+		return lockouts, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lockout LoginLockout
+
+		if err := rows.Scan(
+			&lockout.ID,
+			&lockout.Key,
+			&lockout.Failures,
+			&lockout.LockedUntil,
+			&lockout.UnlockedAt,
+			&lockout.UnlockedBy,
+			&lockout.CreatedAt,
+		); err != nil {
+			return lockouts, err
+		}
+
+		lockouts = append(lockouts, lockout)
+	}
+
+	return lockouts, nil
+}
+
+func UnlockLoginLockoutsByKey(dbConn DBConn, key string, unlockedBy string) error {
+	sql := `UPDATE login_lockouts SET unlocked_at = CURRENT_TIMESTAMP, unlocked_by = $1
+	WHERE key = $2 AND unlocked_at IS NULL`
+
+	if _, err := dbConn.Exec(
+		sql,
+		unlockedBy,
+		key,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeleteAllLoginLockoutByKey(dbConn DBConn, key string) error {
+	sql := `DELETE FROM login_lockouts
+	WHERE key = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		key,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func AnonymizeLoginLockoutUnlockedBy(dbConn DBConn, email string, anonymousEmail string) error {
+	sql := `UPDATE login_lockouts SET unlocked_by = $1
+	WHERE unlocked_by = $2`
+
+	if _, err := dbConn.Exec(
+		sql,
+		anonymousEmail,
+		email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type OidcState struct {
+	StateHash    string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    *time.Time
+	CreatedAt    *time.Time
+	Expired      bool
+}
+
+func (o *OidcState) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&o.StateHash,
+		&o.Provider,
+		&o.CodeVerifier,
+		&o.Nonce,
+		&o.ExpiresAt,
+		&o.CreatedAt,
+		&o.Expired,
+	)
+}
+
+func (o *OidcState) IsExpired() bool {
+	return o.Expired
+}
+
+type OidcCallback struct {
+	State string `json:"state" validate:"required"`
+	Code  string `json:"code" validate:"required"`
+}
+
+func (o *OidcState) Create(dbConn DBConn) error {
+	sql := `INSERT INTO oidc_states (state_hash, provider, code_verifier, nonce)
+	VALUES ($1, $2, $3, $4)
+	RETURNING state_hash, provider, code_verifier, nonce, expires_at, created_at, expires_at < CURRENT_TIMESTAMP`
+
+	return o.scanRow(dbConn.QueryRow(
+		sql,
+		o.StateHash,
+		o.Provider,
+		o.CodeVerifier,
+		o.Nonce,
+	))
+}
+
+func (o *OidcState) Consume(dbConn DBConn) error {
+	sql := `DELETE FROM oidc_states
+	WHERE state_hash = $1 AND provider = $2
+	RETURNING state_hash, provider, code_verifier, nonce, expires_at, created_at, expires_at < CURRENT_TIMESTAMP`
+
+	return o.scanRow(dbConn.QueryRow(
+		sql,
+		o.StateHash,
+		o.Provider,
+	))
+}
+
+func DeleteExpiredOidcStates(dbConn DBConn) error {
+	sql := `DELETE FROM oidc_states
+	WHERE expires_at < CURRENT_TIMESTAMP`
+
+	if _, err := dbConn.Exec(sql); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type PasswordResetToken struct {
+	TokenHash string
+	UserEmail string
+	ExpiresAt *time.Time
+	UsedAt    *time.Time
+	CreatedAt *time.Time
+	Expired   bool
+}
+
+func (p *PasswordResetToken) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&p.TokenHash,
+		&p.UserEmail,
+		&p.ExpiresAt,
+		&p.UsedAt,
+		&p.CreatedAt,
+		&p.Expired,
+	)
+}
+
+func (p *PasswordResetToken) IsExpired() bool {
+	return p.Expired
+}
+
+func (p *PasswordResetToken) IsUsed() bool {
+	return p.UsedAt != nil
+}
+
+type PasswordForgot struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type PasswordReset struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+func (p *PasswordResetToken) Create(dbConn DBConn) error {
+	sql := `INSERT INTO password_reset_tokens (token_hash, user_email)
+	VALUES ($1, $2)
+	RETURNING token_hash, user_email, expires_at, used_at, created_at, expires_at < CURRENT_TIMESTAMP`
+
+	return p.scanRow(dbConn.QueryRow(
+		sql,
+		p.TokenHash,
+		p.UserEmail,
+	))
+}
+
+func (p *PasswordResetToken) GetByTokenHashForUpdate(dbConn DBConn) error {
+	sql := `SELECT token_hash, user_email, expires_at, used_at, created_at, expires_at < CURRENT_TIMESTAMP
+	FROM password_reset_tokens
+	WHERE token_hash = $1
+	FOR UPDATE`
+
+	return p.scanRow(dbConn.QueryRow(
+		sql,
+		p.TokenHash,
+	))
+}
+
+func (p *PasswordResetToken) MarkUsed(dbConn DBConn) error {
+	sql := `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+	WHERE token_hash = $1
+	RETURNING token_hash, user_email, expires_at, used_at, created_at, expires_at < CURRENT_TIMESTAMP`
+
+	return p.scanRow(dbConn.QueryRow(
+		sql,
+		p.TokenHash,
+	))
+}
+
+func InvalidatePasswordResetTokensByUserEmail(dbConn DBConn, email string) error {
+	sql := `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+	WHERE user_email = $1 AND used_at IS NULL`
+
+	if _, err := dbConn.Exec(
+		sql,
+		email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
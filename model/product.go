@@ -2,18 +2,24 @@ package model
 
 import (
 	"database/sql"
+	"ecommerce-api/money"
+	"strconv"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type Product struct {
-	ID          string     `json:"id,omitempty"`
-	Name        string     `json:"name,omitempty"`
-	StoreID     string     `json:"store_id,omitempty"`
-	Description string     `json:"description,omitempty"`
-	Stock       int        `json:"stock,omitempty"`
-	Price       int64      `json:"price,omitempty"`
-	CreatedAt   *time.Time `json:"created_at,omitempty"`
-	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	ID          string      `json:"id,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	StoreID     string      `json:"store_id,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Stock       int         `json:"stock,omitempty"`
+	Price       money.Money `json:"price"`
+	CategoryID  *string     `json:"category_id"`
+	Tags        []string    `json:"tags"`
+	CreatedAt   *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time  `json:"updated_at,omitempty"`
 }
 
 func (p *Product) scanRow(row *sql.Row) error {
@@ -23,7 +29,10 @@ func (p *Product) scanRow(row *sql.Row) error {
 		&p.StoreID,
 		&p.Description,
 		&p.Stock,
-		&p.Price,
+		&p.Price.Amount,
+		&p.Price.Currency,
+		&p.CategoryID,
+		pq.Array(&p.Tags),
 		&p.CreatedAt,
 		&p.UpdatedAt,
 	)
@@ -41,7 +50,10 @@ func scanRowsProduct(rows *sql.Rows) ([]Product, error) {
 			&product.StoreID,
 			&product.Description,
 			&product.Stock,
-			&product.Price,
+			&product.Price.Amount,
+			&product.Price.Currency,
+			&product.CategoryID,
+			pq.Array(&product.Tags),
 			&product.CreatedAt,
 			&product.UpdatedAt,
 		); err != nil {
@@ -55,44 +67,131 @@ func scanRowsProduct(rows *sql.Rows) ([]Product, error) {
 }
 
 type ProductCreate struct {
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	Stock       int    `json:"stock" validate:"required"`
-	Price       int64  `json:"price" validate:"required"`
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description" validate:"required"`
+	Stock       int      `json:"stock" validate:"required"`
+	Price       int64    `json:"price" validate:"required"`
+	CategoryID  string   `json:"category_id" validate:"omitempty,uuid"`
+	Tags        []string `json:"tags" validate:"max=20,dive,max=50"`
 }
 
 func (p *ProductCreate) ToProduct() Product {
-	return Product{
+	product := Product{
 		Name:        p.Name,
 		Description: p.Description,
 		Stock:       p.Stock,
-		Price:       p.Price,
+		Price:       money.New(p.Price, ""),
+		Tags:        p.Tags,
+	}
+	if p.CategoryID != "" {
+		product.CategoryID = &p.CategoryID
 	}
+
+	return product
 }
 
 type ProductUpdate struct {
-	ID          string `json:"id" validate:"required"`
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	Stock       int    `json:"stock" validate:"required"`
-	Price       int64  `json:"price" validate:"required"`
+	ID          string    `json:"id" validate:"required"`
+	Name        string    `json:"name" validate:"required"`
+	Description string    `json:"description" validate:"required"`
+	Stock       int       `json:"stock" validate:"required"`
+	Price       int64     `json:"price" validate:"required"`
+	CategoryID  *string   `json:"category_id" validate:"omitempty,uuid|len=0"`
+	Tags        *[]string `json:"tags" validate:"omitempty,max=20,dive,max=50"`
 }
 
-func (p *ProductUpdate) ToProduct() Product {
-	return Product{
-		ID:          p.ID,
-		Name:        p.Name,
-		Description: p.Description,
-		Stock:       p.Stock,
-		Price:       p.Price,
+func (p *ProductUpdate) ApplyTo(product *Product) {
+	product.Name = p.Name
+	product.Description = p.Description
+	product.Stock = p.Stock
+	product.Price.Amount = p.Price
+
+	if p.CategoryID != nil {
+		product.CategoryID = nil
+		if *p.CategoryID != "" {
+			product.CategoryID = p.CategoryID
+		}
+	}
+
+	if p.Tags != nil {
+		product.Tags = *p.Tags
+	}
+}
+
+type ProductFilter struct {
+	StoreID    string `json:"store_id" validate:"omitempty,uuid"`
+	Currency   string `json:"currency" validate:"omitempty,iso4217"`
+	MinPrice   *int64 `json:"min_price" validate:"omitempty,min=0"`
+	MaxPrice   *int64 `json:"max_price" validate:"omitempty,min=0"`
+	InStock    *bool  `json:"in_stock"`
+	CategoryID string `json:"category_id" validate:"omitempty,uuid"`
+	Tag        string `json:"tag" validate:"omitempty,max=50"`
+}
+
+func (f *ProductFilter) listFilters() []listFilter {
+	var filters []listFilter
+
+	if f.StoreID != "" {
+		filters = append(filters, listFilter{"store_id = ?", []interface{}{f.StoreID}})
+	}
+	if f.Currency != "" {
+		filters = append(filters, listFilter{"currency = ?", []interface{}{f.Currency}})
+	}
+	if f.MinPrice != nil {
+		filters = append(filters, listFilter{"price >= ?", []interface{}{*f.MinPrice}})
+	}
+	if f.MaxPrice != nil {
+		filters = append(filters, listFilter{"price <= ?", []interface{}{*f.MaxPrice}})
+	}
+	if f.InStock != nil && *f.InStock {
+		filters = append(filters, listFilter{"stock > 0", nil})
+	}
+	if f.InStock != nil && !*f.InStock {
+		filters = append(filters, listFilter{"stock <= 0", nil})
 	}
+	if f.CategoryID != "" {
+		filters = append(filters, listFilter{`category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = ?
+				UNION ALL
+				SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id
+			)
+			SELECT id FROM tree
+		)`, []interface{}{f.CategoryID}})
+	}
+	if f.Tag != "" {
+		filters = append(filters, listFilter{"? = ANY(tags)", []interface{}{f.Tag}})
+	}
+
+	return filters
 }
 
-func GetAllProduct(dbConn DBConn) ([]Product, error) {
-	sql := `SELECT id, name, store_id, description, stock, price, created_at, updated_at 
+var productListSpec = listSpec[Product]{
+	sorts: map[string]listSortField[Product]{
+		"created_at": {"created_at", "TIMESTAMP", func(p Product) string { return timeListValue(p.CreatedAt) }},
+		"name":       {"name", "VARCHAR", func(p Product) string { return p.Name }},
+		"price":      {"price", "BIGINT", func(p Product) string { return strconv.FormatInt(p.Price.Amount, 10) }},
+		"stock":      {"stock", "INT", func(p Product) string { return strconv.Itoa(p.Stock) }},
+	},
+	defaultSort: "-created_at",
+	keyColumn:   "id",
+	keyCast:     "UUID",
+	key:         func(p Product) string { return p.ID },
+}
+
+func GetAllProduct(dbConn DBConn, filter ProductFilter, query ListQuery) (ListPage[Product], error) {
+	sql := `SELECT id, name, store_id, description, stock, price, currency, category_id, tags, created_at, updated_at
 	FROM products`
 
-	rows, err := dbConn.Query(sql)
+	return runListQuery(dbConn, productListSpec, sql, filter.listFilters(), query, scanRowsProduct)
+}
+
+func GetAllProductByStoreID(dbConn DBConn, storeID string) ([]Product, error) {
+	sql := `SELECT id, name, store_id, description, stock, price, currency, category_id, tags, created_at, updated_at
+	FROM products
+	WHERE store_id = $1`
+
+	rows, err := dbConn.Query(sql, storeID)
 	if err != nil {
 		return nil, err
 	}
@@ -102,9 +201,9 @@ func GetAllProduct(dbConn DBConn) ([]Product, error) {
 }
 
 func (p *Product) Create(dbConn DBConn) error {
-	sql := `INSERT INTO products (name, store_id, description, stock, price)
-	VALUES ($1, $2, $3, $4, $5)
-	RETURNING id, name, store_id, description, stock, price, created_at, updated_at`
+	sql := `INSERT INTO products (name, store_id, description, stock, price, currency, category_id, tags)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8::TEXT[], '{}'))
+	RETURNING id, name, store_id, description, stock, price, currency, category_id, tags, created_at, updated_at`
 
 	return p.scanRow(dbConn.QueryRow(
 		sql,
@@ -112,27 +211,32 @@ func (p *Product) Create(dbConn DBConn) error {
 		p.StoreID,
 		p.Description,
 		p.Stock,
-		p.Price,
+		p.Price.Amount,
+		p.Price.Currency,
+		p.CategoryID,
+		pq.Array(p.Tags),
 	))
 }
 
 func (p *Product) UpdateByID(dbConn DBConn) error {
-	sql := `UPDATE products SET name = $1, description = $2, stock = $3, price = $4
-	WHERE id = $5
-	RETURNING id, name, store_id, description, stock, price, created_at, updated_at`
+	sql := `UPDATE products SET name = $1, description = $2, stock = $3, price = $4, category_id = $5, tags = COALESCE($6::TEXT[], '{}')
+	WHERE id = $7
+	RETURNING id, name, store_id, description, stock, price, currency, category_id, tags, created_at, updated_at`
 
 	return p.scanRow(dbConn.QueryRow(
 		sql,
 		p.Name,
 		p.Description,
 		p.Stock,
-		p.Price,
+		p.Price.Amount,
+		p.CategoryID,
+		pq.Array(p.Tags),
 		p.ID,
 	))
 }
 
 func (p *Product) GetByID(dbConn DBConn) error {
-	sql := `SELECT id, name, store_id, description, stock, price, created_at, updated_at 
+	sql := `SELECT id, name, store_id, description, stock, price, currency, category_id, tags, created_at, updated_at 
 	FROM products 
 	WHERE id = $1`
 
@@ -141,3 +245,29 @@ func (p *Product) GetByID(dbConn DBConn) error {
 		p.ID,
 	))
 }
+
+func DelistAllProductByStoreID(dbConn DBConn, storeID string) error {
+	sql := `UPDATE products SET stock = 0
+	WHERE store_id = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		storeID,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Product) GetByIDForUpdate(dbConn DBConn) error {
+	sql := `SELECT id, name, store_id, description, stock, price, currency, category_id, tags, created_at, updated_at
+	FROM products
+	WHERE id = $1
+	FOR UPDATE`
+
+	return p.scanRow(dbConn.QueryRow(
+		sql,
+		p.ID,
+	))
+}
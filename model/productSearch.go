@@ -0,0 +1,117 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"unicode"
+
+	"github.com/lib/pq"
+)
+
+type ProductSearch struct {
+	Query string `json:"q" validate:"required,max=200"`
+	Limit int    `json:"limit"`
+}
+
+type ProductHighlight struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ProductSearchResult struct {
+	Product
+	Rank      float64          `json:"rank"`
+	Highlight ProductHighlight `json:"highlight"`
+}
+
+func scanRowsProductSearchResult(rows *sql.Rows) ([]ProductSearchResult, error) {
+	var results []ProductSearchResult
+
+	for rows.Next() {
+		var result ProductSearchResult
+
+		if err := rows.Scan(
+			&result.ID,
+			&result.Name,
+			&result.StoreID,
+			&result.Description,
+			&result.Stock,
+			&result.Price.Amount,
+			&result.Price.Currency,
+			&result.CategoryID,
+			pq.Array(&result.Tags),
+			&result.CreatedAt,
+			&result.UpdatedAt,
+			&result.Rank,
+			&result.Highlight.Name,
+			&result.Highlight.Description,
+		); err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (s *ProductSearch) tsQuery() string {
+	terms := strings.FieldsFunc(s.Query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, term := range terms {
+		terms[i] = strings.ToLower(term) + ":*"
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+func htmlEscapeSql(column string) string {
+	return `replace(replace(replace(replace(replace(` + column + `, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+}
+
+func SearchProduct(dbConn DBConn, search ProductSearch) ([]ProductSearchResult, error) {
+	tsQuery := search.tsQuery()
+	if tsQuery == "" {
+		return []ProductSearchResult{}, nil
+	}
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	sql := `WITH matches AS (
+		SELECT products.*, ts_rank_cd(search_vector, query) AS rank, query
+		FROM products, to_tsquery('english', $1) query
+		WHERE search_vector @@ query
+		ORDER BY rank DESC, id
+		LIMIT $2
+	)
+	SELECT id, name, store_id, description, stock, price, currency, category_id, tags, created_at, updated_at, rank,
+	ts_headline('english', ` + htmlEscapeSql("name") + `, query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+	ts_headline('english', ` + htmlEscapeSql("description") + `, query, 'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" ... "')
+	FROM matches
+	ORDER BY rank DESC, id`
+
+	rows, err := dbConn.Query(sql, tsQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results, err := scanRowsProductSearchResult(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if results == nil {
+		results = []ProductSearchResult{}
+	}
+
+	return results, nil
+}
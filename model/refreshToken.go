@@ -6,33 +6,52 @@ import (
 )
 
 type RefreshToken struct {
-	Token     string
-	UserEmail string
-	ExpiresAt *time.Time
+	Token      string
+	UserEmail  string
+	FamilyID   string
+	ReplacedBy *string
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  *time.Time
+	Expired    bool
 }
 
 func (r *RefreshToken) scanRow(row *sql.Row) error {
 	return row.Scan(
 		&r.Token,
 		&r.UserEmail,
+		&r.FamilyID,
+		&r.ReplacedBy,
 		&r.ExpiresAt,
+		&r.RevokedAt,
+		&r.CreatedAt,
+		&r.Expired,
 	)
 }
 
+func (r *RefreshToken) IsExpired() bool {
+	return r.Expired
+}
+
+func (r *RefreshToken) IsRevoked() bool {
+	return r.RevokedAt != nil
+}
+
 func (r *RefreshToken) Create(dbConn DBConn) error {
-	sql := `INSERT INTO refresh_tokens (user_email)
-	VALUES ($1)
-	RETURNING token, user_email, expires_at`
+	sql := `INSERT INTO refresh_tokens (user_email, family_id)
+	VALUES ($1, $2)
+	RETURNING token, user_email, family_id, replaced_by, expires_at, revoked_at, created_at, COALESCE(expires_at < CURRENT_TIMESTAMP, FALSE)`
 
 	return r.scanRow(dbConn.QueryRow(
 		sql,
 		r.UserEmail,
+		r.FamilyID,
 	))
 }
 
 func (r *RefreshToken) GetByToken(dbConn DBConn) error {
-	sql := `SELECT token, user_email, expires_at 
-	FROM refresh_tokens 
+	sql := `SELECT token, user_email, family_id, replaced_by, expires_at, revoked_at, created_at, COALESCE(expires_at < CURRENT_TIMESTAMP, FALSE)
+	FROM refresh_tokens
 	WHERE token = $1`
 
 	return r.scanRow(dbConn.QueryRow(
@@ -41,8 +60,32 @@ func (r *RefreshToken) GetByToken(dbConn DBConn) error {
 	))
 }
 
+func (r *RefreshToken) GetByTokenForUpdate(dbConn DBConn) error {
+	sql := `SELECT token, user_email, family_id, replaced_by, expires_at, revoked_at, created_at, COALESCE(expires_at < CURRENT_TIMESTAMP, FALSE)
+	FROM refresh_tokens
+	WHERE token = $1
+	FOR UPDATE`
+
+	return r.scanRow(dbConn.QueryRow(
+		sql,
+		r.Token,
+	))
+}
+
+func (r *RefreshToken) Revoke(dbConn DBConn, replacedBy string) error {
+	sql := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP, replaced_by = NULLIF($1, '')::UUID
+	WHERE token = $2
+	RETURNING token, user_email, family_id, replaced_by, expires_at, revoked_at, created_at, COALESCE(expires_at < CURRENT_TIMESTAMP, FALSE)`
+
+	return r.scanRow(dbConn.QueryRow(
+		sql,
+		replacedBy,
+		r.Token,
+	))
+}
+
 func (r *RefreshToken) Delete(dbConn DBConn) error {
-	sql := `DELETE FROM refresh_tokens 
+	sql := `DELETE FROM refresh_tokens
 	WHERE token = $1`
 
 	if _, err := dbConn.Exec(
@@ -54,3 +97,55 @@ func (r *RefreshToken) Delete(dbConn DBConn) error {
 
 	return nil
 }
+
+func RevokeRefreshTokenFamily(dbConn DBConn, familyID string) error {
+	sql := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+	WHERE family_id = $1 AND revoked_at IS NULL`
+
+	if _, err := dbConn.Exec(
+		sql,
+		familyID,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeleteExpiredRefreshTokens(dbConn DBConn) error {
+	sql := `DELETE FROM refresh_tokens
+	WHERE expires_at < CURRENT_TIMESTAMP`
+
+	if _, err := dbConn.Exec(sql); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type RefreshTokenReuseEvent struct {
+	ID        string
+	Token     string
+	FamilyID  string
+	UserEmail string
+	CreatedAt *time.Time
+}
+
+func (e *RefreshTokenReuseEvent) Create(dbConn DBConn) error {
+	sql := `INSERT INTO refresh_token_reuse_events (token, family_id, user_email)
+	VALUES ($1, $2, $3)
+	RETURNING id, token, family_id, user_email, created_at`
+
+	return dbConn.QueryRow(
+		sql,
+		e.Token,
+		e.FamilyID,
+		e.UserEmail,
+	).Scan(
+		&e.ID,
+		&e.Token,
+		&e.FamilyID,
+		&e.UserEmail,
+		&e.CreatedAt,
+	)
+}
@@ -0,0 +1,187 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Session struct {
+	ID         string     `json:"id,omitempty"`
+	UserEmail  string     `json:"user_email,omitempty"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	Current    bool       `json:"current"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+func (s *Session) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&s.ID,
+		&s.UserEmail,
+		&s.UserAgent,
+		&s.IPAddress,
+		&s.CreatedAt,
+		&s.LastUsedAt,
+	)
+}
+
+func scanRowsSession(rows *sql.Rows) ([]Session, error) {
+	var sessions []Session
+
+	for rows.Next() {
+		var session Session
+
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserEmail,
+			&session.UserAgent,
+			&session.IPAddress,
+			&session.CreatedAt,
+			&session.LastUsedAt,
+		); err != nil {
+			return sessions, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+func scanRowsSessionID(rows *sql.Rows) ([]string, error) {
+	var sessionIDs []string
+
+	for rows.Next() {
+		var sessionID string
+
+		if err := rows.Scan(&sessionID); err != nil {
+			return sessionIDs, err
+		}
+
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+
+	return sessionIDs, nil
+}
+
+func (s *Session) Create(dbConn DBConn) error {
+	sql := `INSERT INTO sessions (user_email, user_agent, ip_address)
+	VALUES ($1, $2, $3)
+	RETURNING id, user_email, user_agent, ip_address, created_at, last_used_at`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.UserEmail,
+		s.UserAgent,
+		s.IPAddress,
+	))
+}
+
+func (s *Session) Touch(dbConn DBConn) error {
+	sql := `UPDATE sessions SET user_agent = $1, ip_address = $2, last_used_at = CURRENT_TIMESTAMP
+	WHERE id = $3
+	RETURNING id, user_email, user_agent, ip_address, created_at, last_used_at`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.UserAgent,
+		s.IPAddress,
+		s.ID,
+	))
+}
+
+func (s *Session) GetByIDAndUserEmail(dbConn DBConn) error {
+	sql := `SELECT id, user_email, user_agent, ip_address, created_at, last_used_at
+	FROM sessions
+	WHERE id = $1 AND user_email = $2`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.ID,
+		s.UserEmail,
+	))
+}
+
+func (s *Session) Delete(dbConn DBConn) error {
+	sql := `DELETE FROM sessions
+	WHERE id = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		s.ID,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func GetAllActiveSessionByUserEmail(dbConn DBConn, email string) ([]Session, error) {
+	sql := `SELECT id, user_email, user_agent, ip_address, created_at, last_used_at
+	FROM sessions
+	WHERE user_email = $1 AND EXISTS (
+		SELECT 1 FROM refresh_tokens
+		WHERE refresh_tokens.family_id = sessions.id
+		AND refresh_tokens.revoked_at IS NULL
+		AND refresh_tokens.expires_at > CURRENT_TIMESTAMP
+	)
+	ORDER BY last_used_at DESC`
+
+	rows, err := dbConn.Query(sql, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsSession(rows)
+}
+
+func DeleteAllSessionByUserEmail(dbConn DBConn, email string) ([]string, error) {
+	sql := `DELETE FROM sessions
+	WHERE user_email = $1
+	RETURNING id`
+
+	rows, err := dbConn.Query(
+		sql,
+		email,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsSessionID(rows)
+}
+
+func DeleteAllSessionByUserEmailExcept(dbConn DBConn, email string, sessionID string) ([]string, error) {
+	sql := `DELETE FROM sessions
+	WHERE user_email = $1 AND id::TEXT <> $2
+	RETURNING id`
+
+	rows, err := dbConn.Query(
+		sql,
+		email,
+		sessionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsSessionID(rows)
+}
+
+func DeleteStaleSessions(dbConn DBConn) error {
+	sql := `DELETE FROM sessions
+	WHERE NOT EXISTS (
+		SELECT 1 FROM refresh_tokens
+		WHERE refresh_tokens.family_id = sessions.id
+	)`
+
+	if _, err := dbConn.Exec(sql); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ type Store struct {
 	ID         string     `json:"id,omitempty"`
 	OwnerEmail string     `json:"owner_email,omitempty"`
 	Name       string     `json:"name,omitempty"`
+	Currency   string     `json:"currency,omitempty"`
 	CreatedAt  *time.Time `json:"created_at,omitempty"`
 	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
 }
@@ -18,6 +19,7 @@ func (s *Store) scanRow(row *sql.Row) error {
 		&s.ID,
 		&s.OwnerEmail,
 		&s.Name,
+		&s.Currency,
 		&s.CreatedAt,
 		&s.UpdatedAt,
 	)
@@ -33,6 +35,7 @@ func scanRowsStore(rows *sql.Rows) ([]Store, error) {
 			&store.ID,
 			&store.OwnerEmail,
 			&store.Name,
+			&store.Currency,
 			&store.CreatedAt,
 			&store.UpdatedAt,
 		); err != nil {
@@ -46,38 +49,63 @@ func scanRowsStore(rows *sql.Rows) ([]Store, error) {
 }
 
 type StoreRegister struct {
-	Name string `json:"name" validate:"required"`
+	Name     string `json:"name" validate:"required"`
+	Currency string `json:"currency" validate:"omitempty,iso4217"`
 }
 
 func (s *StoreRegister) ToStore() Store {
-	return Store{Name: s.Name}
+	return Store{Name: s.Name, Currency: s.Currency}
 }
 
 func (s *Store) Create(dbConn DBConn) error {
-	sql := `INSERT INTO stores (owner_email, name) VALUES ($1, $2) RETURNING id, name, created_at, updated_at`
+	sql := `INSERT INTO stores (owner_email, name, currency) VALUES ($1, $2, $3) RETURNING id, owner_email, name, currency, created_at, updated_at`
 
 	return s.scanRow(dbConn.QueryRow(
 		sql,
 		s.OwnerEmail,
 		s.Name,
+		s.Currency,
 	))
 }
 
-func GetAllStore(dbConn DBConn) ([]Store, error) {
-	var stores []Store
-	sql := `SELECT id, owner_email, name, created_at, updated_at FROM stores`
+type StoreFilter struct {
+	OwnerEmail string `json:"owner_email" validate:"omitempty,email"`
+	Currency   string `json:"currency" validate:"omitempty,iso4217"`
+}
 
-	rows, err := dbConn.Query(sql)
-	if err != nil {
-		return stores, err
+func (f *StoreFilter) listFilters() []listFilter {
+	var filters []listFilter
+
+	if f.OwnerEmail != "" {
+		filters = append(filters, listFilter{"owner_email = ?", []interface{}{f.OwnerEmail}})
+	}
+	if f.Currency != "" {
+		filters = append(filters, listFilter{"currency = ?", []interface{}{f.Currency}})
 	}
-	defer rows.Close()
 
-	return scanRowsStore(rows)
+	return filters
+}
+
+var storeListSpec = listSpec[Store]{
+	sorts: map[string]listSortField[Store]{
+		"created_at": {"created_at", "TIMESTAMP", func(s Store) string { return timeListValue(s.CreatedAt) }},
+		"name":       {"name", "VARCHAR", func(s Store) string { return s.Name }},
+	},
+	defaultSort: "-created_at",
+	keyColumn:   "id",
+	keyCast:     "UUID",
+	key:         func(s Store) string { return s.ID },
+}
+
+func GetAllStore(dbConn DBConn, filter StoreFilter, query ListQuery) (ListPage[Store], error) {
+	sql := `SELECT id, owner_email, name, currency, created_at, updated_at
+	FROM stores`
+
+	return runListQuery(dbConn, storeListSpec, sql, filter.listFilters(), query, scanRowsStore)
 }
 
 func (s *Store) GetByID(dbConn DBConn) error {
-	sql := `SELECT id, owner_email, name, created_at, updated_at 
+	sql := `SELECT id, owner_email, name, currency, created_at, updated_at 
 	FROM stores 
 	WHERE id = $1`
 
@@ -88,7 +116,7 @@ func (s *Store) GetByID(dbConn DBConn) error {
 }
 
 func (s *Store) GetByOwnerEmail(dbConn DBConn) error {
-	sql := `SELECT id, owner_email, name, created_at, updated_at 
+	sql := `SELECT id, owner_email, name, currency, created_at, updated_at 
 	FROM stores 
 	WHERE owner_email = $1`
 
@@ -101,7 +129,7 @@ func (s *Store) GetByOwnerEmail(dbConn DBConn) error {
 func (s *Store) UpdateByOwnerEmail(dbConn *sql.DB) error {
 	sql := `UPDATE stores SET name = $1 
 	WHERE owner_email = $2 
-	RETURNING id, owner_email, name, created_at, updated_at`
+	RETURNING id, owner_email, name, currency, created_at, updated_at`
 
 	return s.scanRow(dbConn.QueryRow(
 		sql,
@@ -109,3 +137,15 @@ func (s *Store) UpdateByOwnerEmail(dbConn *sql.DB) error {
 		s.OwnerEmail,
 	))
 }
+
+func (s *Store) GetByIDForUpdate(dbConn DBConn) error {
+	sql := `SELECT id, owner_email, name, currency, created_at, updated_at
+	FROM stores
+	WHERE id = $1
+	FOR UPDATE`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.ID,
+	))
+}
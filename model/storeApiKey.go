@@ -0,0 +1,172 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	ApiKeyScopeProductRead  = "product:read"
+	ApiKeyScopeProductWrite = "product:write"
+)
+
+type StoreApiKey struct {
+	ID         string     `json:"id,omitempty"`
+	StoreID    string     `json:"store_id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Key        string     `json:"key,omitempty"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
+
+func (s *StoreApiKey) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&s.ID,
+		&s.StoreID,
+		&s.Name,
+		&s.Prefix,
+		&s.KeyHash,
+		pq.Array(&s.Scopes),
+		&s.LastUsedAt,
+		&s.RevokedAt,
+		&s.CreatedAt,
+	)
+}
+
+func scanRowsStoreApiKey(rows *sql.Rows) ([]StoreApiKey, error) {
+	var apiKeys []StoreApiKey
+
+	for rows.Next() {
+		var apiKey StoreApiKey
+
+		if err := rows.Scan(
+			&apiKey.ID,
+			&apiKey.StoreID,
+			&apiKey.Name,
+			&apiKey.Prefix,
+			&apiKey.KeyHash,
+			pq.Array(&apiKey.Scopes),
+			&apiKey.LastUsedAt,
+			&apiKey.RevokedAt,
+			&apiKey.CreatedAt,
+		); err != nil {
+			return apiKeys, err
+		}
+
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, nil
+}
+
+func (s *StoreApiKey) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+func (s *StoreApiKey) HasScope(scope string) bool {
+	for _, apiKeyScope := range s.Scopes {
+		if apiKeyScope == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type StoreApiKeyCreate struct {
+	Name   string   `json:"name" validate:"required"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=product:read product:write"`
+}
+
+func (s *StoreApiKeyCreate) ToStoreApiKey() StoreApiKey {
+	return StoreApiKey{
+		Name:   s.Name,
+		Scopes: s.Scopes,
+	}
+}
+
+func (s *StoreApiKey) Create(dbConn DBConn) error {
+	sql := `INSERT INTO store_api_keys (store_id, name, prefix, key_hash, scopes)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, store_id, name, prefix, key_hash, scopes, last_used_at, revoked_at, created_at`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.StoreID,
+		s.Name,
+		s.Prefix,
+		s.KeyHash,
+		pq.Array(s.Scopes),
+	))
+}
+
+func (s *StoreApiKey) GetByKeyHash(dbConn DBConn) error {
+	sql := `SELECT id, store_id, name, prefix, key_hash, scopes, last_used_at, revoked_at, created_at
+	FROM store_api_keys
+	WHERE key_hash = $1`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.KeyHash,
+	))
+}
+
+func (s *StoreApiKey) Touch(dbConn DBConn) error {
+	sql := `UPDATE store_api_keys SET last_used_at = CURRENT_TIMESTAMP
+	WHERE id = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		s.ID,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *StoreApiKey) RevokeByIDAndStoreID(dbConn DBConn) error {
+	sql := `UPDATE store_api_keys SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+	WHERE id = $1 AND store_id = $2
+	RETURNING id, store_id, name, prefix, key_hash, scopes, last_used_at, revoked_at, created_at`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.ID,
+		s.StoreID,
+	))
+}
+
+func GetAllStoreApiKeyByStoreID(dbConn DBConn, storeID string) ([]StoreApiKey, error) {
+	sql := `SELECT id, store_id, name, prefix, key_hash, scopes, last_used_at, revoked_at, created_at
+	FROM store_api_keys
+	WHERE store_id = $1
+	ORDER BY created_at DESC`
+
+	rows, err := dbConn.Query(sql, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsStoreApiKey(rows)
+}
+
+func RevokeAllStoreApiKeyByStoreID(dbConn DBConn, storeID string) error {
+	sql := `UPDATE store_api_keys SET revoked_at = CURRENT_TIMESTAMP
+	WHERE store_id = $1 AND revoked_at IS NULL`
+
+	if _, err := dbConn.Exec(
+		sql,
+		storeID,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	StoreLedgerEntrySale               = "sale"
+	StoreLedgerEntryRefund             = "refund"
+	StoreLedgerEntryWithdrawal         = "withdrawal"
+	StoreLedgerEntryWithdrawalReversal = "withdrawal_reversal"
+	StoreLedgerEntryOpeningBalance     = "opening_balance"
+)
+
+type StoreLedgerEntry struct {
+	ID          string     `json:"id,omitempty"`
+	StoreID     string     `json:"store_id,omitempty"`
+	Type        string     `json:"type,omitempty"`
+	GrossAmount int64      `json:"gross_amount"`
+	Commission  int64      `json:"commission"`
+	Amount      int64      `json:"amount"`
+	ReferenceID *string    `json:"reference_id,omitempty"`
+	AvailableAt *time.Time `json:"available_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+}
+
+func (s *StoreLedgerEntry) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&s.ID,
+		&s.StoreID,
+		&s.Type,
+		&s.GrossAmount,
+		&s.Commission,
+		&s.Amount,
+		&s.ReferenceID,
+		&s.AvailableAt,
+		&s.CreatedAt,
+	)
+}
+
+func scanRowsStoreLedgerEntry(rows *sql.Rows) ([]StoreLedgerEntry, error) {
+	var entries []StoreLedgerEntry
+
+	for rows.Next() {
+		var entry StoreLedgerEntry
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.StoreID,
+			&entry.Type,
+			&entry.GrossAmount,
+			&entry.Commission,
+			&entry.Amount,
+			&entry.ReferenceID,
+			&entry.AvailableAt,
+			&entry.CreatedAt,
+		); err != nil {
+			return entries, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (s *StoreLedgerEntry) Create(dbConn DBConn, hold time.Duration) error {
+	sql := `INSERT INTO store_ledger_entries (store_id, type, gross_amount, commission, amount, reference_id, available_at)
+	VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP + make_interval(secs => $7))
+	RETURNING id, store_id, type, gross_amount, commission, amount, reference_id, available_at, created_at`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.StoreID,
+		s.Type,
+		s.GrossAmount,
+		s.Commission,
+		s.Amount,
+		s.ReferenceID,
+		hold.Seconds(),
+	))
+}
+
+func GetAllStoreLedgerEntryByStoreID(dbConn DBConn, storeID string) ([]StoreLedgerEntry, error) {
+	sql := `SELECT id, store_id, type, gross_amount, commission, amount, reference_id, available_at, created_at
+	FROM store_ledger_entries
+	WHERE store_id = $1
+	ORDER BY created_at DESC`
+
+	rows, err := dbConn.Query(sql, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsStoreLedgerEntry(rows)
+}
+
+type StoreBalance struct {
+	StoreID   string `json:"store_id"`
+	Available int64  `json:"available"`
+	Pending   int64  `json:"pending"`
+}
+
+func (s *StoreBalance) GetByStoreID(dbConn DBConn) error {
+	sql := `SELECT
+		COALESCE(SUM(amount) FILTER (WHERE available_at <= CURRENT_TIMESTAMP), 0),
+		COALESCE(SUM(amount) FILTER (WHERE available_at > CURRENT_TIMESTAMP), 0)
+	FROM store_ledger_entries
+	WHERE store_id = $1`
+
+	return dbConn.QueryRow(
+		sql,
+		s.StoreID,
+	).Scan(
+		&s.Available,
+		&s.Pending,
+	)
+}
+
+type StoreEarnings struct {
+	StoreID     string             `json:"store_id"`
+	Sales       int                `json:"sales"`
+	GrossAmount int64              `json:"gross_amount"`
+	Commission  int64              `json:"commission"`
+	NetAmount   int64              `json:"net_amount"`
+	Entries     []StoreLedgerEntry `json:"entries"`
+}
+
+func (s *StoreEarnings) GetByStoreID(dbConn DBConn) error {
+	sql := `SELECT
+		COUNT(*) FILTER (WHERE type = 'sale') - COUNT(*) FILTER (WHERE type = 'refund'),
+		COALESCE(SUM(gross_amount), 0),
+		COALESCE(SUM(commission), 0),
+		COALESCE(SUM(amount), 0)
+	FROM store_ledger_entries
+	WHERE store_id = $1 AND type IN ('sale', 'refund', 'opening_balance')`
+
+	if err := dbConn.QueryRow(
+		sql,
+		s.StoreID,
+	).Scan(
+		&s.Sales,
+		&s.GrossAmount,
+		&s.Commission,
+		&s.NetAmount,
+	); err != nil {
+		return err
+	}
+
+	entries, err := GetAllStoreLedgerEntryByStoreID(dbConn, s.StoreID)
+	if err != nil {
+		return err
+	}
+	s.Entries = entries
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type StoreOpeningBalance struct {
+	StoreID     string     `json:"store_id"`
+	GrossAmount int64      `json:"gross_amount"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+}
+
+func (s *StoreOpeningBalance) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&s.StoreID,
+		&s.GrossAmount,
+		&s.ReleasedAt,
+		&s.CreatedAt,
+	)
+}
+
+func (s *StoreOpeningBalance) GetByStoreIDForUpdate(dbConn DBConn) error {
+	sql := `SELECT store_id, gross_amount, released_at, created_at
+	FROM store_opening_balances
+	WHERE store_id = $1
+	FOR UPDATE`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.StoreID,
+	))
+}
+
+func (s *StoreOpeningBalance) Release(dbConn DBConn) error {
+	sql := `UPDATE store_opening_balances SET released_at = CURRENT_TIMESTAMP
+	WHERE store_id = $1
+	RETURNING store_id, gross_amount, released_at, created_at`
+
+	return s.scanRow(dbConn.QueryRow(
+		sql,
+		s.StoreID,
+	))
+}
+
+func (s *StoreOpeningBalance) IsReleased() bool {
+	return s.ReleasedAt != nil
+}
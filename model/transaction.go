@@ -2,15 +2,23 @@ package model
 
 import (
 	"database/sql"
+	"ecommerce-api/money"
+	"strconv"
 	"time"
 )
 
 type Transaction struct {
-	ID        string     `json:"id,omitempty"`
-	UserEmail string     `json:"user_email,omitempty"`
-	ProductID string     `json:"product_id,omitempty"`
-	Quantity  int        `json:"quantity,omitempty"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
+	ID              string           `json:"id,omitempty"`
+	UserEmail       string           `json:"user_email,omitempty"`
+	ProductID       string           `json:"product_id,omitempty"`
+	Quantity        int              `json:"quantity,omitempty"`
+	Amount          money.Money      `json:"amount"`
+	ProductAmount   money.Money      `json:"product_amount"`
+	ExchangeRate    string           `json:"exchange_rate"`
+	Commission      int64            `json:"commission"`
+	ShippingAddress *AddressSnapshot `json:"shipping_address,omitempty"`
+	RefundedAt      *time.Time       `json:"refunded_at,omitempty"`
+	CreatedAt       *time.Time       `json:"created_at,omitempty"`
 }
 
 func (t *Transaction) scanRow(row *sql.Row) error {
@@ -19,6 +27,14 @@ func (t *Transaction) scanRow(row *sql.Row) error {
 		&t.UserEmail,
 		&t.ProductID,
 		&t.Quantity,
+		&t.Amount.Amount,
+		&t.Amount.Currency,
+		&t.ProductAmount.Amount,
+		&t.ProductAmount.Currency,
+		&t.ExchangeRate,
+		&t.Commission,
+		&t.ShippingAddress,
+		&t.RefundedAt,
 		&t.CreatedAt,
 	)
 }
@@ -34,6 +50,14 @@ func scanRowsTransaction(rows *sql.Rows) ([]Transaction, error) {
 			&transaction.UserEmail,
 			&transaction.ProductID,
 			&transaction.Quantity,
+			&transaction.Amount.Amount,
+			&transaction.Amount.Currency,
+			&transaction.ProductAmount.Amount,
+			&transaction.ProductAmount.Currency,
+			&transaction.ExchangeRate,
+			&transaction.Commission,
+			&transaction.ShippingAddress,
+			&transaction.RefundedAt,
 			&transaction.CreatedAt,
 		); err != nil {
 			return transactions, err
@@ -47,7 +71,8 @@ func scanRowsTransaction(rows *sql.Rows) ([]Transaction, error) {
 
 type TransactionCreate struct {
 	ProductID string `json:"product_id" validate:"required"`
-	Quantity  int    `json:"quantity" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"required,min=1"`
+	AddressID string `json:"address_id" validate:"omitempty,uuid"`
 }
 
 func (t *TransactionCreate) ToTransaction() Transaction {
@@ -58,36 +83,74 @@ func (t *TransactionCreate) ToTransaction() Transaction {
 }
 
 func (t *Transaction) Create(dbConn DBConn) error {
-	sql := `INSERT INTO transactions (user_email, product_id, quantity) 
-	VALUES ($1, $2, $3) 
-	RETURNING id, user_email, product_id, quantity, created_at`
+	sql := `INSERT INTO transactions (user_email, product_id, quantity, amount, currency, product_amount, product_currency, exchange_rate, commission, shipping_address)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING id, user_email, product_id, quantity, amount, currency, product_amount, product_currency, exchange_rate, commission, shipping_address, refunded_at, created_at`
 
 	return t.scanRow(dbConn.QueryRow(
 		sql,
 		t.UserEmail,
 		t.ProductID,
 		t.Quantity,
+		t.Amount.Amount,
+		t.Amount.Currency,
+		t.ProductAmount.Amount,
+		t.ProductAmount.Currency,
+		t.ExchangeRate,
+		t.Commission,
+		t.ShippingAddress,
 	))
 }
 
-func GetAllTransaction(dbConn DBConn) ([]Transaction, error) {
-	sql := `SELECT id, user_email, product_id, quantity, created_at 
-	FROM transactions`
+type TransactionFilter struct {
+	UserEmail string `json:"user_email" validate:"omitempty,email"`
+	ProductID string `json:"product_id" validate:"omitempty,uuid"`
+	Refunded  *bool  `json:"refunded"`
+}
 
-	rows, err := dbConn.Query(sql)
-	if err != nil {
-		return nil, err
+func (f *TransactionFilter) listFilters() []listFilter {
+	var filters []listFilter
+
+	if f.UserEmail != "" {
+		filters = append(filters, listFilter{"user_email = ?", []interface{}{f.UserEmail}})
+	}
+	if f.ProductID != "" {
+		filters = append(filters, listFilter{"product_id = ?", []interface{}{f.ProductID}})
+	}
+	if f.Refunded != nil && *f.Refunded {
+		filters = append(filters, listFilter{"refunded_at IS NOT NULL", nil})
+	}
+	if f.Refunded != nil && !*f.Refunded {
+		filters = append(filters, listFilter{"refunded_at IS NULL", nil})
 	}
-	defer rows.Close()
 
-	return scanRowsTransaction(rows)
+	return filters
 }
 
-func GetAllTransactionByUserEmail(dbConn DBConn, email string) ([]Transaction, error) {
-	sql := `SELECT id, user_email, product_id, quantity, created_at
+var transactionListSpec = listSpec[Transaction]{
+	sorts: map[string]listSortField[Transaction]{
+		"created_at": {"created_at", "TIMESTAMP", func(t Transaction) string { return timeListValue(t.CreatedAt) }},
+		"amount":     {"amount", "BIGINT", func(t Transaction) string { return strconv.FormatInt(t.Amount.Amount, 10) }},
+	},
+	defaultSort: "-created_at",
+	keyColumn:   "id",
+	keyCast:     "UUID",
+	key:         func(t Transaction) string { return t.ID },
+}
+
+func GetAllTransaction(dbConn DBConn, filter TransactionFilter, query ListQuery) (ListPage[Transaction], error) {
+	sql := `SELECT id, user_email, product_id, quantity, amount, currency, product_amount, product_currency, exchange_rate, commission, shipping_address, refunded_at, created_at
 	FROM transactions`
 
-	rows, err := dbConn.Query(sql)
+	return runListQuery(dbConn, transactionListSpec, sql, filter.listFilters(), query, scanRowsTransaction)
+}
+
+func GetAllTransactionByUserEmail(dbConn DBConn, email string) ([]Transaction, error) {
+	sql := `SELECT id, user_email, product_id, quantity, amount, currency, product_amount, product_currency, exchange_rate, commission, shipping_address, refunded_at, created_at
+	FROM transactions
+	WHERE user_email = $1`
+
+	rows, err := dbConn.Query(sql, email)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +160,7 @@ func GetAllTransactionByUserEmail(dbConn DBConn, email string) ([]Transaction, e
 }
 
 func (t *Transaction) GetByID(dbConn DBConn) error {
-	sql := `SELECT id, user_email, product_id, quantity, created_at
+	sql := `SELECT id, user_email, product_id, quantity, amount, currency, product_amount, product_currency, exchange_rate, commission, shipping_address, refunded_at, created_at
 	FROM transactions
 	WHERE id = $1`
 
@@ -106,3 +169,40 @@ func (t *Transaction) GetByID(dbConn DBConn) error {
 		t.ID,
 	))
 }
+
+func (t *Transaction) GetByIDForUpdate(dbConn DBConn) error {
+	sql := `SELECT id, user_email, product_id, quantity, amount, currency, product_amount, product_currency, exchange_rate, commission, shipping_address, refunded_at, created_at
+	FROM transactions
+	WHERE id = $1
+	FOR UPDATE`
+
+	return t.scanRow(dbConn.QueryRow(
+		sql,
+		t.ID,
+	))
+}
+
+func (t *Transaction) MarkRefunded(dbConn DBConn) error {
+	sql := `UPDATE transactions SET refunded_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND refunded_at IS NULL
+	RETURNING id, user_email, product_id, quantity, amount, currency, product_amount, product_currency, exchange_rate, commission, shipping_address, refunded_at, created_at`
+
+	return t.scanRow(dbConn.QueryRow(
+		sql,
+		t.ID,
+	))
+}
+
+func ClearShippingAddressByUserEmail(dbConn DBConn, email string) error {
+	sql := `UPDATE transactions SET shipping_address = NULL
+	WHERE user_email = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
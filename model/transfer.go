@@ -0,0 +1,121 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Transfer struct {
+	ID             string     `json:"id,omitempty"`
+	SenderEmail    string     `json:"sender_email,omitempty"`
+	RecipientEmail string     `json:"recipient_email,omitempty"`
+	Amount         int64      `json:"amount"`
+	Currency       string     `json:"currency,omitempty"`
+	IdempotencyKey string     `json:"-"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+}
+
+func (t *Transfer) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&t.ID,
+		&t.SenderEmail,
+		&t.RecipientEmail,
+		&t.Amount,
+		&t.Currency,
+		&t.IdempotencyKey,
+		&t.CreatedAt,
+	)
+}
+
+func scanRowsTransfer(rows *sql.Rows) ([]Transfer, error) {
+	var transfers []Transfer
+
+	for rows.Next() {
+		var transfer Transfer
+
+		if err := rows.Scan(
+			&transfer.ID,
+			&transfer.SenderEmail,
+			&transfer.RecipientEmail,
+			&transfer.Amount,
+			&transfer.Currency,
+			&transfer.IdempotencyKey,
+			&transfer.CreatedAt,
+		); err != nil {
+			return transfers, err
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
+
+type TransferCreate struct {
+	RecipientEmail string `json:"recipient_email" validate:"required,email"`
+	Amount         int64  `json:"amount" validate:"required,min=1"`
+	IdempotencyKey string `json:"idempotency_key" validate:"required,max=255"`
+}
+
+func (t *TransferCreate) ToTransfer() Transfer {
+	return Transfer{
+		RecipientEmail: t.RecipientEmail,
+		Amount:         t.Amount,
+		IdempotencyKey: t.IdempotencyKey,
+	}
+}
+
+func (t *Transfer) Create(dbConn DBConn) error {
+	sql := `INSERT INTO transfers (sender_email, recipient_email, amount, currency, idempotency_key)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, sender_email, recipient_email, amount, currency, idempotency_key, created_at`
+
+	return t.scanRow(dbConn.QueryRow(
+		sql,
+		t.SenderEmail,
+		t.RecipientEmail,
+		t.Amount,
+		t.Currency,
+		t.IdempotencyKey,
+	))
+}
+
+func (t *Transfer) GetBySenderEmailAndIdempotencyKey(dbConn DBConn) error {
+	sql := `SELECT id, sender_email, recipient_email, amount, currency, idempotency_key, created_at
+	FROM transfers
+	WHERE sender_email = $1 AND idempotency_key = $2`
+
+	return t.scanRow(dbConn.QueryRow(
+		sql,
+		t.SenderEmail,
+		t.IdempotencyKey,
+	))
+}
+
+func GetTodayTransferTotalBySenderEmail(dbConn DBConn, email string) (int64, error) {
+	sql := `SELECT COALESCE(SUM(amount), 0)
+	FROM transfers
+	WHERE sender_email = $1 AND created_at >= date_trunc('day', CURRENT_TIMESTAMP)`
+
+	var total int64
+	if err := dbConn.QueryRow(sql, email).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func GetAllTransferByUserEmail(dbConn DBConn, email string) ([]Transfer, error) {
+	sql := `SELECT id, sender_email, recipient_email, amount, currency, idempotency_key, created_at
+	FROM transfers
+	WHERE sender_email = $1 OR recipient_email = $1
+	ORDER BY created_at DESC`
+
+	rows, err := dbConn.Query(sql, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsTransfer(rows)
+}
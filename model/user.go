@@ -2,17 +2,31 @@ package model
 
 import (
 	"database/sql"
+	"ecommerce-api/money"
 	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	RoleAdmin  = "admin"
+	RoleSeller = "seller"
+	RoleBuyer  = "buyer"
 )
 
 type User struct {
-	Email     string     `json:"email,omitempty"`
-	FirstName string     `json:"first_name,omitempty"`
-	LastName  string     `json:"last_name,omitempty"`
-	Password  string     `json:"-"`
-	Balance   int64      `json:"balance"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Email           string      `json:"email,omitempty"`
+	FirstName       string      `json:"first_name,omitempty"`
+	LastName        string      `json:"last_name,omitempty"`
+	Password        string      `json:"-"`
+	Balance         money.Money `json:"balance"`
+	Roles           []string    `json:"roles,omitempty"`
+	Avatar          *Avatar     `json:"avatar,omitempty"`
+	EmailVerifiedAt *time.Time  `json:"email_verified_at,omitempty"`
+	BannedAt        *time.Time  `json:"banned_at,omitempty"`
+	DeletedAt       *time.Time  `json:"deleted_at,omitempty"`
+	CreatedAt       *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time  `json:"updated_at,omitempty"`
 }
 
 func (u *User) scanRow(row *sql.Row) error {
@@ -21,7 +35,13 @@ func (u *User) scanRow(row *sql.Row) error {
 		&u.FirstName,
 		&u.LastName,
 		&u.Password,
-		&u.Balance,
+		&u.Balance.Amount,
+		&u.Balance.Currency,
+		pq.Array(&u.Roles),
+		&u.Avatar,
+		&u.EmailVerifiedAt,
+		&u.BannedAt,
+		&u.DeletedAt,
 		&u.CreatedAt,
 		&u.UpdatedAt,
 	)
@@ -38,7 +58,13 @@ func scanRowsUser(rows *sql.Rows) ([]User, error) {
 			&user.FirstName,
 			&user.LastName,
 			&user.Password,
-			&user.Balance,
+			&user.Balance.Amount,
+			&user.Balance.Currency,
+			pq.Array(&user.Roles),
+			&user.Avatar,
+			&user.EmailVerifiedAt,
+			&user.BannedAt,
+			&user.DeletedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		); err != nil {
@@ -63,11 +89,18 @@ func (u *UserLogin) ToUser() User {
 	}
 }
 
+type UserLoginResult struct {
+	AccessToken       string `json:"access_token,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+}
+
 type UserRegister struct {
 	Email     string `json:"email" validate:"required,email"`
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" validate:"required"`
 	Password  string `json:"password" validate:"required"`
+	Currency  string `json:"currency" validate:"omitempty,iso4217"`
 }
 
 func (u *UserRegister) ToUser() User {
@@ -76,9 +109,15 @@ func (u *UserRegister) ToUser() User {
 		FirstName: u.FirstName,
 		LastName:  u.LastName,
 		Password:  u.Password,
+		Balance:   money.New(0, u.Currency),
 	}
 }
 
+type UserPasswordChange struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
 type UserUpdate struct {
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" validate:"required"`
@@ -92,9 +131,9 @@ func (u *UserUpdate) ToUser() User {
 }
 
 func (u *User) Create(dbConn DBConn) error {
-	sql := `INSERT INTO users (email, first_name, last_name, password) 
-	VALUES ($1, $2, $3, $4) 
-	RETURNING email, first_name, last_name, password, balance, created_at, updated_at`
+	sql := `INSERT INTO users (email, first_name, last_name, password, currency) 
+	VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'USD')) 
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
 
 	return u.scanRow(dbConn.QueryRow(
 		sql,
@@ -102,13 +141,14 @@ func (u *User) Create(dbConn DBConn) error {
 		u.FirstName,
 		u.LastName,
 		u.Password,
+		u.Balance.Currency,
 	))
 }
 
 func (u *User) Update(dbConn DBConn) error {
 	sql := `UPDATE users SET first_name = $1, last_name = $2
 	WHERE email = $3
-	RETURNING email, first_name, last_name, password, balance, created_at, updated_at`
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
 
 	return u.scanRow(dbConn.QueryRow(
 		sql,
@@ -121,17 +161,17 @@ func (u *User) Update(dbConn DBConn) error {
 func (u *User) UpdateBalance(dbConn DBConn) error {
 	sql := `UPDATE users SET balance = $1
 	WHERE email = $2
-	RETURNING email, first_name, last_name, password, balance, created_at, updated_at`
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
 
 	return u.scanRow(dbConn.QueryRow(
 		sql,
-		u.Balance,
+		u.Balance.Amount,
 		u.Email,
 	))
 }
 
 func (u *User) GetByEmail(dbConn DBConn) error {
-	sql := `SELECT email, first_name, last_name, password, balance, created_at, updated_at
+	sql := `SELECT email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at
 	FROM users WHERE email = $1`
 
 	return u.scanRow(dbConn.QueryRow(
@@ -140,15 +180,208 @@ func (u *User) GetByEmail(dbConn DBConn) error {
 	))
 }
 
-func GetAllUsers(dnConn DBConn) ([]User, error) {
-	var users []User
-	sql := `SELECT email, first_name, last_name, password, balance, created_at, updated_at FROM users`
+func (u *User) GetByEmailForUpdate(dbConn DBConn) error {
+	sql := `SELECT email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at
+	FROM users WHERE email = $1
+	FOR UPDATE`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.Email,
+	))
+}
+
+type UserFilter struct {
+	Role   string `json:"role" validate:"omitempty,oneof=admin seller buyer"`
+	Banned *bool  `json:"banned"`
+}
+
+func (f *UserFilter) listFilters() []listFilter {
+	var filters []listFilter
+
+	if f.Role != "" {
+		filters = append(filters, listFilter{"?::VARCHAR = ANY(roles)", []interface{}{f.Role}})
+	}
+	if f.Banned != nil && *f.Banned {
+		filters = append(filters, listFilter{"banned_at IS NOT NULL", nil})
+	}
+	if f.Banned != nil && !*f.Banned {
+		filters = append(filters, listFilter{"banned_at IS NULL", nil})
+	}
+
+	return filters
+}
+
+var userListSpec = listSpec[User]{
+	sorts: map[string]listSortField[User]{
+		"created_at": {"created_at", "TIMESTAMP", func(u User) string { return timeListValue(u.CreatedAt) }},
+		"email":      {"email", "VARCHAR", func(u User) string { return u.Email }},
+	},
+	defaultSort: "-created_at",
+	keyColumn:   "email",
+	keyCast:     "VARCHAR",
+	key:         func(u User) string { return u.Email },
+}
+
+func GetAllUsers(dbConn DBConn, filter UserFilter, query ListQuery) (ListPage[User], error) {
+	sql := `SELECT email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at
+	FROM users`
+
+	return runListQuery(dbConn, userListSpec, sql, filter.listFilters(), query, scanRowsUser)
+}
+
+func (u *User) HasRole(roles ...string) bool {
+	for _, userRole := range u.Roles {
+		for _, role := range roles {
+			if userRole == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
 
-	rows, err := dnConn.Query(sql)
-	if err != nil {
-		return users, err
+func (u *User) IsBanned() bool {
+	return u.BannedAt != nil
+}
+
+func (u *User) AddRole(dbConn DBConn, role string) error {
+	sql := `UPDATE users SET roles = array_append(roles, $1::VARCHAR)
+	WHERE email = $2 AND NOT ($1::VARCHAR = ANY(roles))`
+
+	if _, err := dbConn.Exec(
+		sql,
+		role,
+		u.Email,
+	); err != nil {
+		return err
 	}
-	defer rows.Close()
 
-	return scanRowsUser(rows)
+	return u.GetByEmail(dbConn)
+}
+
+func (u *User) UpdateRoles(dbConn DBConn) error {
+	sql := `UPDATE users SET roles = $1
+	WHERE email = $2
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		pq.Array(u.Roles),
+		u.Email,
+	))
+}
+
+type UserRolesUpdate struct {
+	Email string   `json:"email" validate:"required,email"`
+	Roles []string `json:"roles" validate:"required,min=1,dive,oneof=admin seller buyer"`
+}
+
+func (u *UserRolesUpdate) ToUser() User {
+	return User{
+		Email: u.Email,
+		Roles: u.Roles,
+	}
+}
+
+func (u *User) UpdatePassword(dbConn DBConn) error {
+	sql := `UPDATE users SET password = $1
+	WHERE email = $2
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.Password,
+		u.Email,
+	))
+}
+
+func (u *User) MarkEmailVerified(dbConn DBConn) error {
+	sql := `UPDATE users SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP)
+	WHERE email = $1
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.Email,
+	))
+}
+
+func (u *User) Ban(dbConn DBConn) error {
+	sql := `UPDATE users SET banned_at = COALESCE(banned_at, CURRENT_TIMESTAMP)
+	WHERE email = $1
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.Email,
+	))
+}
+
+func (u *User) Unban(dbConn DBConn) error {
+	sql := `UPDATE users SET banned_at = NULL
+	WHERE email = $1
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.Email,
+	))
+}
+
+func (u *User) DeleteCredentials(dbConn DBConn) error {
+	sql := `WITH deleted_refresh_tokens AS (
+		DELETE FROM refresh_tokens WHERE user_email = $1
+	), deleted_reuse_events AS (
+		DELETE FROM refresh_token_reuse_events WHERE user_email = $1
+	), deleted_password_reset_tokens AS (
+		DELETE FROM password_reset_tokens WHERE user_email = $1
+	), deleted_email_verification_tokens AS (
+		DELETE FROM email_verification_tokens WHERE user_email = $1
+	), deleted_recovery_codes AS (
+		DELETE FROM totp_recovery_codes WHERE user_email = $1
+	), deleted_challenges AS (
+		DELETE FROM two_factor_challenges WHERE user_email = $1
+	), deleted_totp AS (
+		DELETE FROM user_totp WHERE user_email = $1
+	)
+	DELETE FROM user_identities WHERE user_email = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		u.Email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *User) Anonymize(dbConn DBConn, anonymousEmail string) error {
+	sql := `UPDATE users SET email = $1, first_name = 'Deleted', last_name = 'User', password = '',
+	roles = '{}', avatar = NULL, email_verified_at = NULL, deleted_at = CURRENT_TIMESTAMP
+	WHERE email = $2
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		anonymousEmail,
+		u.Email,
+	))
+}
+
+func (u *User) UpdateAvatar(dbConn DBConn) error {
+	sql := `UPDATE users SET avatar = $1
+	WHERE email = $2
+	RETURNING email, first_name, last_name, password, balance, currency, roles, avatar, email_verified_at, banned_at, deleted_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.Avatar,
+		u.Email,
+	))
 }
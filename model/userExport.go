@@ -0,0 +1,57 @@
+package model
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+type UserExport struct {
+	User          User                `json:"user"`
+	Store         *Store              `json:"store,omitempty"`
+	Products      []Product           `json:"products"`
+	Transactions  []Transaction       `json:"transactions"`
+	Transfers     []Transfer          `json:"transfers"`
+	LedgerEntries []WalletLedgerEntry `json:"ledger_entries"`
+	Addresses     []Address           `json:"addresses"`
+	Sessions      []Session           `json:"sessions"`
+	ExportedAt    time.Time           `json:"exported_at"`
+}
+
+func (e *UserExport) WriteZip(w io.Writer) error {
+	zipWriter := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", e.User},
+		{"store.json", e.Store},
+		{"products.json", e.Products},
+		{"transactions.json", e.Transactions},
+		{"transfers.json", e.Transfers},
+		{"ledger.json", e.LedgerEntries},
+		{"addresses.json", e.Addresses},
+		{"sessions.json", e.Sessions},
+	}
+
+	for _, file := range files {
+		fileWriter, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: e.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(fileWriter)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
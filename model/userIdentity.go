@@ -0,0 +1,47 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type UserIdentity struct {
+	Provider  string     `json:"provider,omitempty"`
+	Subject   string     `json:"subject,omitempty"`
+	UserEmail string     `json:"user_email,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+func (u *UserIdentity) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&u.Provider,
+		&u.Subject,
+		&u.UserEmail,
+		&u.CreatedAt,
+	)
+}
+
+func (u *UserIdentity) Create(dbConn DBConn) error {
+	sql := `INSERT INTO user_identities (provider, subject, user_email)
+	VALUES ($1, $2, $3)
+	RETURNING provider, subject, user_email, created_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.Provider,
+		u.Subject,
+		u.UserEmail,
+	))
+}
+
+func (u *UserIdentity) GetByProviderAndSubject(dbConn DBConn) error {
+	sql := `SELECT provider, subject, user_email, created_at
+	FROM user_identities
+	WHERE provider = $1 AND subject = $2`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.Provider,
+		u.Subject,
+	))
+}
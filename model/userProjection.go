@@ -0,0 +1,52 @@
+package model
+
+import (
+	"ecommerce-api/money"
+	"time"
+)
+
+type UserPublic struct {
+	Email     string  `json:"email,omitempty"`
+	FirstName string  `json:"first_name,omitempty"`
+	LastName  string  `json:"last_name,omitempty"`
+	Avatar    *Avatar `json:"avatar,omitempty"`
+}
+
+type UserPrivate struct {
+	Email           string      `json:"email,omitempty"`
+	FirstName       string      `json:"first_name,omitempty"`
+	LastName        string      `json:"last_name,omitempty"`
+	Balance         money.Money `json:"balance"`
+	Roles           []string    `json:"roles,omitempty"`
+	Avatar          *Avatar     `json:"avatar,omitempty"`
+	EmailVerifiedAt *time.Time  `json:"email_verified_at,omitempty"`
+	BannedAt        *time.Time  `json:"banned_at,omitempty"`
+	DeletedAt       *time.Time  `json:"deleted_at,omitempty"`
+	CreatedAt       *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time  `json:"updated_at,omitempty"`
+}
+
+func (u *User) ToPublic() UserPublic {
+	return UserPublic{
+		Email:     u.Email,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Avatar:    u.Avatar,
+	}
+}
+
+func (u *User) ToPrivate() UserPrivate {
+	return UserPrivate{
+		Email:           u.Email,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		Balance:         u.Balance,
+		Roles:           u.Roles,
+		Avatar:          u.Avatar,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		BannedAt:        u.BannedAt,
+		DeletedAt:       u.DeletedAt,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+}
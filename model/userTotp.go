@@ -0,0 +1,244 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type UserTotp struct {
+	UserEmail    string
+	Secret       string
+	LastUsedStep int64
+	EnabledAt    *time.Time
+	CreatedAt    *time.Time
+	UpdatedAt    *time.Time
+}
+
+func (u *UserTotp) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&u.UserEmail,
+		&u.Secret,
+		&u.LastUsedStep,
+		&u.EnabledAt,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+	)
+}
+
+func (u *UserTotp) IsEnabled() bool {
+	return u.EnabledAt != nil
+}
+
+type TotpEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningUri string `json:"provisioning_uri"`
+}
+
+type TotpConfirm struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type TotpDisable struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TwoFactorVerify struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+func (u *UserTotp) Upsert(dbConn DBConn) error {
+	sql := `INSERT INTO user_totp (user_email, secret)
+	VALUES ($1, $2)
+	ON CONFLICT (user_email) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, enabled_at = NULL
+	RETURNING user_email, secret, last_used_step, enabled_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.UserEmail,
+		u.Secret,
+	))
+}
+
+func (u *UserTotp) GetByUserEmail(dbConn DBConn) error {
+	sql := `SELECT user_email, secret, last_used_step, enabled_at, created_at, updated_at
+	FROM user_totp
+	WHERE user_email = $1`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.UserEmail,
+	))
+}
+
+func (u *UserTotp) GetByUserEmailForUpdate(dbConn DBConn) error {
+	sql := `SELECT user_email, secret, last_used_step, enabled_at, created_at, updated_at
+	FROM user_totp
+	WHERE user_email = $1
+	FOR UPDATE`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.UserEmail,
+	))
+}
+
+func (u *UserTotp) Enable(dbConn DBConn) error {
+	sql := `UPDATE user_totp SET enabled_at = CURRENT_TIMESTAMP, last_used_step = $1
+	WHERE user_email = $2
+	RETURNING user_email, secret, last_used_step, enabled_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.LastUsedStep,
+		u.UserEmail,
+	))
+}
+
+func (u *UserTotp) UpdateLastUsedStep(dbConn DBConn) error {
+	sql := `UPDATE user_totp SET last_used_step = $1
+	WHERE user_email = $2
+	RETURNING user_email, secret, last_used_step, enabled_at, created_at, updated_at`
+
+	return u.scanRow(dbConn.QueryRow(
+		sql,
+		u.LastUsedStep,
+		u.UserEmail,
+	))
+}
+
+func (u *UserTotp) Delete(dbConn DBConn) error {
+	sql := `DELETE FROM user_totp
+	WHERE user_email = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		u.UserEmail,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type TotpRecoveryCode struct {
+	ID        string
+	UserEmail string
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt *time.Time
+}
+
+func (t *TotpRecoveryCode) Create(dbConn DBConn) error {
+	sql := `INSERT INTO totp_recovery_codes (user_email, code_hash)
+	VALUES ($1, $2)
+	RETURNING id, user_email, code_hash, used_at, created_at`
+
+	return dbConn.QueryRow(
+		sql,
+		t.UserEmail,
+		t.CodeHash,
+	).Scan(
+		&t.ID,
+		&t.UserEmail,
+		&t.CodeHash,
+		&t.UsedAt,
+		&t.CreatedAt,
+	)
+}
+
+func (t *TotpRecoveryCode) Consume(dbConn DBConn) error {
+	sql := `UPDATE totp_recovery_codes SET used_at = CURRENT_TIMESTAMP
+	WHERE user_email = $1 AND code_hash = $2 AND used_at IS NULL
+	RETURNING id, user_email, code_hash, used_at, created_at`
+
+	return dbConn.QueryRow(
+		sql,
+		t.UserEmail,
+		t.CodeHash,
+	).Scan(
+		&t.ID,
+		&t.UserEmail,
+		&t.CodeHash,
+		&t.UsedAt,
+		&t.CreatedAt,
+	)
+}
+
+func DeleteAllTotpRecoveryCodeByUserEmail(dbConn DBConn, email string) error {
+	sql := `DELETE FROM totp_recovery_codes
+	WHERE user_email = $1`
+
+	if _, err := dbConn.Exec(
+		sql,
+		email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type TwoFactorChallenge struct {
+	TokenHash string
+	UserEmail string
+	ExpiresAt *time.Time
+	UsedAt    *time.Time
+	CreatedAt *time.Time
+	Expired   bool
+}
+
+func (t *TwoFactorChallenge) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&t.TokenHash,
+		&t.UserEmail,
+		&t.ExpiresAt,
+		&t.UsedAt,
+		&t.CreatedAt,
+		&t.Expired,
+	)
+}
+
+func (t *TwoFactorChallenge) IsExpired() bool {
+	return t.Expired
+}
+
+func (t *TwoFactorChallenge) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+func (t *TwoFactorChallenge) Create(dbConn DBConn) error {
+	sql := `INSERT INTO two_factor_challenges (token_hash, user_email)
+	VALUES ($1, $2)
+	RETURNING token_hash, user_email, expires_at, used_at, created_at, expires_at < CURRENT_TIMESTAMP`
+
+	return t.scanRow(dbConn.QueryRow(
+		sql,
+		t.TokenHash,
+		t.UserEmail,
+	))
+}
+
+func (t *TwoFactorChallenge) GetByTokenHashForUpdate(dbConn DBConn) error {
+	sql := `SELECT token_hash, user_email, expires_at, used_at, created_at, expires_at < CURRENT_TIMESTAMP
+	FROM two_factor_challenges
+	WHERE token_hash = $1
+	FOR UPDATE`
+
+	return t.scanRow(dbConn.QueryRow(
+		sql,
+		t.TokenHash,
+	))
+}
+
+func (t *TwoFactorChallenge) MarkUsed(dbConn DBConn) error {
+	sql := `UPDATE two_factor_challenges SET used_at = CURRENT_TIMESTAMP
+	WHERE token_hash = $1
+	RETURNING token_hash, user_email, expires_at, used_at, created_at, expires_at < CURRENT_TIMESTAMP`
+
+	return t.scanRow(dbConn.QueryRow(
+		sql,
+		t.TokenHash,
+	))
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	LedgerEntryTopUp       = "topup"
+	LedgerEntryPurchase    = "purchase"
+	LedgerEntryRefund      = "refund"
+	LedgerEntryAdjustment  = "adjustment"
+	LedgerEntryTransferIn  = "transfer_in"
+	LedgerEntryTransferOut = "transfer_out"
+)
+
+type WalletLedgerEntry struct {
+	ID                string     `json:"id,omitempty"`
+	UserEmail         string     `json:"user_email,omitempty"`
+	Type              string     `json:"type,omitempty"`
+	Amount            int64      `json:"amount"`
+	BalanceAfter      int64      `json:"balance_after"`
+	ReferenceID       *string    `json:"reference_id,omitempty"`
+	Description       string     `json:"description,omitempty"`
+	CounterpartyEmail *string    `json:"counterparty_email,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+}
+
+func (w *WalletLedgerEntry) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&w.ID,
+		&w.UserEmail,
+		&w.Type,
+		&w.Amount,
+		&w.BalanceAfter,
+		&w.ReferenceID,
+		&w.Description,
+		&w.CreatedAt,
+	)
+}
+
+func scanRowsWalletLedgerEntry(rows *sql.Rows) ([]WalletLedgerEntry, error) {
+	var entries []WalletLedgerEntry
+
+	for rows.Next() {
+		var entry WalletLedgerEntry
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.UserEmail,
+			&entry.Type,
+			&entry.Amount,
+			&entry.BalanceAfter,
+			&entry.ReferenceID,
+			&entry.Description,
+			&entry.CounterpartyEmail,
+			&entry.CreatedAt,
+		); err != nil {
+			return entries, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+type WalletTopUp struct {
+	Amount int64 `json:"amount" validate:"required,min=1,max=100000000"`
+}
+
+type WalletAdjustment struct {
+	Amount      int64  `json:"amount" validate:"required,ne=0"`
+	Description string `json:"description" validate:"required,max=255"`
+}
+
+func (w *WalletLedgerEntry) Create(dbConn DBConn) error {
+	sql := `INSERT INTO wallet_ledger_entries (user_email, type, amount, balance_after, reference_id, description)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, user_email, type, amount, balance_after, reference_id, description, created_at`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		w.UserEmail,
+		w.Type,
+		w.Amount,
+		w.BalanceAfter,
+		w.ReferenceID,
+		w.Description,
+	))
+}
+
+func GetAllWalletLedgerEntryByUserEmail(dbConn DBConn, email string) ([]WalletLedgerEntry, error) {
+	sql := `SELECT wallet_ledger_entries.id, wallet_ledger_entries.user_email, wallet_ledger_entries.type, wallet_ledger_entries.amount,
+	wallet_ledger_entries.balance_after, wallet_ledger_entries.reference_id, wallet_ledger_entries.description,
+	CASE wallet_ledger_entries.type
+		WHEN 'transfer_out' THEN transfers.recipient_email
+		WHEN 'transfer_in' THEN transfers.sender_email
+	END,
+	wallet_ledger_entries.created_at
+	FROM wallet_ledger_entries
+	LEFT JOIN transfers ON transfers.id = wallet_ledger_entries.reference_id
+		AND wallet_ledger_entries.type IN ('transfer_out', 'transfer_in')
+	WHERE wallet_ledger_entries.user_email = $1
+	ORDER BY wallet_ledger_entries.created_at DESC`
+
+	rows, err := dbConn.Query(sql, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsWalletLedgerEntry(rows)
+}
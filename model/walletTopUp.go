@@ -0,0 +1,157 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	WalletTopUpPending   = "pending"
+	WalletTopUpSucceeded = "succeeded"
+	WalletTopUpFailed    = "failed"
+)
+
+type WalletTopUpIntent struct {
+	ID                string     `json:"id,omitempty"`
+	UserEmail         string     `json:"user_email,omitempty"`
+	Amount            int64      `json:"amount"`
+	Currency          string     `json:"currency,omitempty"`
+	Status            string     `json:"status,omitempty"`
+	ProviderReference *string    `json:"provider_reference,omitempty"`
+	CheckoutUrl       *string    `json:"checkout_url,omitempty"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+func (w *WalletTopUpIntent) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&w.ID,
+		&w.UserEmail,
+		&w.Amount,
+		&w.Currency,
+		&w.Status,
+		&w.ProviderReference,
+		&w.CheckoutUrl,
+		&w.CompletedAt,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+}
+
+func scanRowsWalletTopUpIntent(rows *sql.Rows) ([]WalletTopUpIntent, error) {
+	var topUps []WalletTopUpIntent
+
+	for rows.Next() {
+		var topUp WalletTopUpIntent
+
+		if err := rows.Scan(
+			&topUp.ID,
+			&topUp.UserEmail,
+			&topUp.Amount,
+			&topUp.Currency,
+			&topUp.Status,
+			&topUp.ProviderReference,
+			&topUp.CheckoutUrl,
+			&topUp.CompletedAt,
+			&topUp.CreatedAt,
+			&topUp.UpdatedAt,
+		); err != nil {
+			return topUps, err
+		}
+
+		topUps = append(topUps, topUp)
+	}
+
+	return topUps, nil
+}
+
+func (w *WalletTopUpIntent) Create(dbConn DBConn) error {
+	sql := `INSERT INTO wallet_top_ups (user_email, amount, currency)
+	VALUES ($1, $2, $3)
+	RETURNING id, user_email, amount, currency, status, provider_reference, checkout_url, completed_at, created_at, updated_at`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		w.UserEmail,
+		w.Amount,
+		w.Currency,
+	))
+}
+
+func (w *WalletTopUpIntent) GetByIDAndUserEmail(dbConn DBConn) error {
+	sql := `SELECT id, user_email, amount, currency, status, provider_reference, checkout_url, completed_at, created_at, updated_at
+	FROM wallet_top_ups
+	WHERE id = $1 AND user_email = $2`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		w.ID,
+		w.UserEmail,
+	))
+}
+
+func (w *WalletTopUpIntent) GetByIDForUpdate(dbConn DBConn) error {
+	sql := `SELECT id, user_email, amount, currency, status, provider_reference, checkout_url, completed_at, created_at, updated_at
+	FROM wallet_top_ups
+	WHERE id = $1
+	FOR UPDATE`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		w.ID,
+	))
+}
+
+func (w *WalletTopUpIntent) UpdateProviderIntent(dbConn DBConn) error {
+	sql := `UPDATE wallet_top_ups SET provider_reference = $1, checkout_url = $2
+	WHERE id = $3
+	RETURNING id, user_email, amount, currency, status, provider_reference, checkout_url, completed_at, created_at, updated_at`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		w.ProviderReference,
+		w.CheckoutUrl,
+		w.ID,
+	))
+}
+
+func (w *WalletTopUpIntent) Complete(dbConn DBConn, status string) error {
+	sql := `UPDATE wallet_top_ups SET status = $1, completed_at = CURRENT_TIMESTAMP
+	WHERE id = $2
+	RETURNING id, user_email, amount, currency, status, provider_reference, checkout_url, completed_at, created_at, updated_at`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		status,
+		w.ID,
+	))
+}
+
+func GetTodayWalletTopUpTotalByUserEmail(dbConn DBConn, email string) (int64, error) {
+	sql := `SELECT COALESCE(SUM(amount), 0)
+	FROM wallet_top_ups
+	WHERE user_email = $1 AND status <> 'failed' AND created_at >= date_trunc('day', CURRENT_TIMESTAMP)`
+
+	var total int64
+	if err := dbConn.QueryRow(sql, email).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func GetAllWalletTopUpIntentByUserEmail(dbConn DBConn, email string) ([]WalletTopUpIntent, error) {
+	sql := `SELECT id, user_email, amount, currency, status, provider_reference, checkout_url, completed_at, created_at, updated_at
+	FROM wallet_top_ups
+	WHERE user_email = $1
+	ORDER BY created_at DESC`
+
+	rows, err := dbConn.Query(sql, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsWalletTopUpIntent(rows)
+}
@@ -0,0 +1,208 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	WithdrawalRequested = "requested"
+	WithdrawalApproved  = "approved"
+	WithdrawalRejected  = "rejected"
+	WithdrawalPaid      = "paid"
+	WithdrawalFailed    = "failed"
+)
+
+type Withdrawal struct {
+	ID              string     `json:"id,omitempty"`
+	StoreID         string     `json:"store_id,omitempty"`
+	Amount          int64      `json:"amount"`
+	Status          string     `json:"status,omitempty"`
+	RejectionReason *string    `json:"rejection_reason,omitempty"`
+	PayoutReference *string    `json:"payout_reference,omitempty"`
+	ReviewedBy      *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	PaidAt          *time.Time `json:"paid_at,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+func (w *Withdrawal) scanRow(row *sql.Row) error {
+	return row.Scan(
+		&w.ID,
+		&w.StoreID,
+		&w.Amount,
+		&w.Status,
+		&w.RejectionReason,
+		&w.PayoutReference,
+		&w.ReviewedBy,
+		&w.ReviewedAt,
+		&w.PaidAt,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+}
+
+func scanRowsWithdrawal(rows *sql.Rows) ([]Withdrawal, error) {
+	var withdrawals []Withdrawal
+
+	for rows.Next() {
+		var withdrawal Withdrawal
+
+		if err := rows.Scan(
+			&withdrawal.ID,
+			&withdrawal.StoreID,
+			&withdrawal.Amount,
+			&withdrawal.Status,
+			&withdrawal.RejectionReason,
+			&withdrawal.PayoutReference,
+			&withdrawal.ReviewedBy,
+			&withdrawal.ReviewedAt,
+			&withdrawal.PaidAt,
+			&withdrawal.CreatedAt,
+			&withdrawal.UpdatedAt,
+		); err != nil {
+			return withdrawals, err
+		}
+
+		withdrawals = append(withdrawals, withdrawal)
+	}
+
+	return withdrawals, nil
+}
+
+type WithdrawalCreate struct {
+	Amount int64 `json:"amount" validate:"required,min=1"`
+}
+
+func (w *WithdrawalCreate) ToWithdrawal() Withdrawal {
+	return Withdrawal{
+		Amount: w.Amount,
+	}
+}
+
+type WithdrawalReject struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type WithdrawalFail struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+func (w *Withdrawal) Create(dbConn DBConn) error {
+	sql := `INSERT INTO withdrawals (store_id, amount)
+	VALUES ($1, $2)
+	RETURNING id, store_id, amount, status, rejection_reason, payout_reference, reviewed_by, reviewed_at, paid_at, created_at, updated_at`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		w.StoreID,
+		w.Amount,
+	))
+}
+
+func (w *Withdrawal) GetByIDForUpdate(dbConn DBConn) error {
+	sql := `SELECT id, store_id, amount, status, rejection_reason, payout_reference, reviewed_by, reviewed_at, paid_at, created_at, updated_at
+	FROM withdrawals
+	WHERE id = $1
+	FOR UPDATE`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		w.ID,
+	))
+}
+
+func (w *Withdrawal) Approve(dbConn DBConn, reviewedBy string) error {
+	sql := `UPDATE withdrawals SET status = 'approved', reviewed_by = $1, reviewed_at = CURRENT_TIMESTAMP
+	WHERE id = $2
+	RETURNING id, store_id, amount, status, rejection_reason, payout_reference, reviewed_by, reviewed_at, paid_at, created_at, updated_at`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		reviewedBy,
+		w.ID,
+	))
+}
+
+func (w *Withdrawal) Reject(dbConn DBConn, reviewedBy string, reason string) error {
+	sql := `UPDATE withdrawals SET status = 'rejected', rejection_reason = $1, reviewed_by = $2, reviewed_at = CURRENT_TIMESTAMP
+	WHERE id = $3
+	RETURNING id, store_id, amount, status, rejection_reason, payout_reference, reviewed_by, reviewed_at, paid_at, created_at, updated_at`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		reason,
+		reviewedBy,
+		w.ID,
+	))
+}
+
+func (w *Withdrawal) MarkPaid(dbConn DBConn, payoutReference string) error {
+	sql := `UPDATE withdrawals SET status = 'paid', payout_reference = $1, paid_at = CURRENT_TIMESTAMP
+	WHERE id = $2
+	RETURNING id, store_id, amount, status, rejection_reason, payout_reference, reviewed_by, reviewed_at, paid_at, created_at, updated_at`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		payoutReference,
+		w.ID,
+	))
+}
+
+func (w *Withdrawal) MarkFailed(dbConn DBConn, reason string) error {
+	sql := `UPDATE withdrawals SET status = 'failed', rejection_reason = $1
+	WHERE id = $2
+	RETURNING id, store_id, amount, status, rejection_reason, payout_reference, reviewed_by, reviewed_at, paid_at, created_at, updated_at`
+
+	return w.scanRow(dbConn.QueryRow(
+		sql,
+		reason,
+		w.ID,
+	))
+}
+
+func GetAllWithdrawal(dbConn DBConn, status string) ([]Withdrawal, error) {
+	sql := `SELECT id, store_id, amount, status, rejection_reason, payout_reference, reviewed_by, reviewed_at, paid_at, created_at, updated_at
+	FROM withdrawals
+	WHERE $1 = '' OR status = $1
+	ORDER BY created_at DESC`
+
+	rows, err := dbConn.Query(sql, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsWithdrawal(rows)
+}
+
+func GetAllWithdrawalByStoreID(dbConn DBConn, storeID string) ([]Withdrawal, error) {
+	sql := `SELECT id, store_id, amount, status, rejection_reason, payout_reference, reviewed_by, reviewed_at, paid_at, created_at, updated_at
+	FROM withdrawals
+	WHERE store_id = $1
+	ORDER BY created_at DESC`
+
+	rows, err := dbConn.Query(sql, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRowsWithdrawal(rows)
+}
+
+func AnonymizeWithdrawalReviewedBy(dbConn DBConn, email string, anonymousEmail string) error {
+	sql := `UPDATE withdrawals SET reviewed_by = $1
+	WHERE reviewed_by = $2`
+
+	if _, err := dbConn.Exec(
+		sql,
+		anonymousEmail,
+		email,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
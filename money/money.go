@@ -0,0 +1,115 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("Currency mismatch")
+	ErrInvalidRate      = errors.New("Invalid exchange rate")
+)
+
+var minorUnits = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"IDR": 0,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"UGX": 0,
+	"VND": 0,
+}
+
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func New(amount int64, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: strings.ToUpper(currency),
+	}
+}
+
+func MinorUnits(currency string) int {
+	if units, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return units
+	}
+	return 2
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return m, ErrCurrencyMismatch
+	}
+
+	return New(m.Amount+other.Amount, m.Currency), nil
+}
+
+func (m Money) Multiply(quantity int64) Money {
+	return New(m.Amount*quantity, m.Currency)
+}
+
+func (m Money) Negate() Money {
+	return New(-m.Amount, m.Currency)
+}
+
+func (m Money) String() string {
+	units := MinorUnits(m.Currency)
+	if units == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	amount := new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(units))
+	return fmt.Sprintf("%s %s", amount.FloatString(units), m.Currency)
+}
+
+func ParseRate(rate string) (*big.Rat, error) {
+	parsed, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || parsed.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+
+	return parsed, nil
+}
+
+func Convert(m Money, currency string, rate *big.Rat) Money {
+	currency = strings.ToUpper(currency)
+
+	converted := new(big.Rat).SetInt64(m.Amount)
+	converted.Mul(converted, rate)
+	converted.Mul(converted, new(big.Rat).SetFrac(pow10(MinorUnits(currency)), pow10(MinorUnits(m.Currency))))
+
+	return New(round(converted), currency)
+}
+
+func round(value *big.Rat) int64 {
+	numerator := new(big.Int).Set(value.Num())
+	denominator := value.Denom()
+
+	negative := numerator.Sign() < 0
+	numerator.Abs(numerator)
+
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if new(big.Int).Mul(remainder, big.NewInt(2)).Cmp(denominator) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+
+	if negative {
+		quotient.Neg(quotient)
+	}
+
+	return quotient.Int64()
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
@@ -0,0 +1,102 @@
+package money
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Money
+		currency string
+		rate     string
+		want     Money
+	}{
+		{"same exponent", New(10000, "USD"), "EUR", "0.9", New(9000, "EUR")},
+		{"into zero decimal currency", New(10000, "USD"), "JPY", "150", New(15000, "JPY")},
+		{"from zero decimal currency", New(15000, "JPY"), "USD", "1/150", New(10000, "USD")},
+		{"into three decimal currency", New(100, "USD"), "KWD", "0.307", New(307, "KWD")},
+		{"half rounds away from zero", New(5, "USD"), "EUR", "0.5", New(3, "EUR")},
+		{"below half rounds down", New(7, "USD"), "EUR", "0.3", New(2, "EUR")},
+		{"negative half rounds away from zero", New(-5, "USD"), "EUR", "0.5", New(-3, "EUR")},
+		{"repeating inverse rate", New(100, "USD"), "EUR", "1/3", New(33, "EUR")},
+		{"tiny rate", New(1000000, "IDR"), "BHD", "0.0000000000001", New(0, "BHD")},
+		{"lowercase currency", New(100, "USD"), "eur", "1", New(100, "EUR")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rate, err := ParseRate(test.rate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := Convert(test.amount, test.currency, rate); got != test.want {
+				t.Fatalf("Convert(%v, %s, %s) = %v, want %v", test.amount, test.currency, test.rate, got, test.want)
+			}
+		})
+	}
+}
+
+func TestConvertKeepsTinyRatesExact(t *testing.T) {
+	rate, err := ParseRate("0.0000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rate.RatString(); got != "1/10000000000000" {
+		t.Fatalf("rate = %s, want 1/10000000000000", got)
+	}
+
+	if got := Convert(New(10000000000000, "JPY"), "JPY", rate); got != New(1, "JPY") {
+		t.Fatalf("Convert = %v, want 1 JPY", got)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		numerator   int64
+		denominator int64
+		want        int64
+	}{
+		{0, 1, 0},
+		{7, 1, 7},
+		{1, 3, 0},
+		{1, 2, 1},
+		{2, 3, 1},
+		{5, 2, 3},
+		{-1, 3, 0},
+		{-1, 2, -1},
+		{-5, 2, -3},
+		{-7, 3, -2},
+	}
+
+	for _, test := range tests {
+		if got := round(big.NewRat(test.numerator, test.denominator)); got != test.want {
+			t.Errorf("round(%d/%d) = %d, want %d", test.numerator, test.denominator, got, test.want)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		rate    string
+		wantErr bool
+	}{
+		{"150", false},
+		{" 0.5 ", false},
+		{"1/3", false},
+		{"0", true},
+		{"-1", true},
+		{"abc", true},
+		{"", true},
+	}
+
+	for _, test := range tests {
+		_, err := ParseRate(test.rate)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseRate(%q) error = %v, wantErr %v", test.rate, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const mockKeyID = "mock"
+
+type MockUser struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+type mockAuthorization struct {
+	clientID      string
+	redirectUri   string
+	codeChallenge string
+	nonce         string
+	user          MockUser
+	expiresAt     time.Time
+}
+
+type MockProvider struct {
+	clientID     string
+	clientSecret string
+	key          *rsa.PrivateKey
+
+	mutex sync.Mutex
+	user  MockUser
+	codes map[string]mockAuthorization
+}
+
+func NewMockProvider(clientID string, clientSecret string, user MockUser) (*MockProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MockProvider{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		key:          key,
+		user:         user,
+		codes:        map[string]mockAuthorization{},
+	}, nil
+}
+
+func (m *MockProvider) SetUser(user MockUser) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.user = user
+}
+
+func (m *MockProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for suffix, handler := range map[string]func(http.ResponseWriter, *http.Request, string){
+		"/.well-known/openid-configuration": m.discovery,
+		"/authorize":                        m.authorize,
+		"/token":                            m.token,
+		"/jwks":                             m.jwks,
+	} {
+		if strings.HasSuffix(r.URL.Path, suffix) {
+			handler(w, r, mockIssuer(r, strings.TrimSuffix(r.URL.Path, suffix)))
+			return
+		}
+	}
+
+	http.NotFound(w, r)
+}
+
+func mockIssuer(r *http.Request, path string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host + path
+}
+
+func (m *MockProvider) discovery(w http.ResponseWriter, r *http.Request, issuer string) {
+	writeMockJson(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (m *MockProvider) authorize(w http.ResponseWriter, r *http.Request, issuer string) {
+	query := r.URL.Query()
+
+	redirectUri, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectUri.IsAbs() {
+		writeMockError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	if query.Get("client_id") != m.clientID {
+		writeMockError(w, http.StatusBadRequest, "unauthorized_client")
+		return
+	}
+
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		writeMockError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	code, err := randomMockValue()
+	if err != nil {
+		writeMockError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	m.mutex.Lock()
+	m.codes[code] = mockAuthorization{
+		clientID:      m.clientID,
+		redirectUri:   redirectUri.String(),
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		user:          m.user,
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	m.mutex.Unlock()
+
+	values := redirectUri.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirectUri.RawQuery = values.Encode()
+
+	http.Redirect(w, r, redirectUri.String(), http.StatusFound)
+}
+
+func (m *MockProvider) token(w http.ResponseWriter, r *http.Request, issuer string) {
+	if r.Method != http.MethodPost {
+		writeMockError(w, http.StatusMethodNotAllowed, "invalid_request")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeMockError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	if !m.authenticateClient(r) {
+		writeMockError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	m.mutex.Lock()
+	authorization, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mutex.Unlock()
+
+	if !ok || time.Now().After(authorization.expiresAt) || authorization.redirectUri != r.PostForm.Get("redirect_uri") {
+		writeMockError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	codeChallenge := CodeChallengeS256(r.PostForm.Get("code_verifier"))
+	if subtle.ConstantTimeCompare([]byte(codeChallenge), []byte(authorization.codeChallenge)) != 1 {
+		writeMockError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            issuer,
+		"sub":            authorization.user.Subject,
+		"aud":            authorization.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          authorization.nonce,
+		"email":          authorization.user.Email,
+		"email_verified": authorization.user.EmailVerified,
+		"given_name":     authorization.user.GivenName,
+		"family_name":    authorization.user.FamilyName,
+		"name":           strings.TrimSpace(authorization.user.GivenName + " " + authorization.user.FamilyName),
+	})
+	idToken.Header["kid"] = mockKeyID
+
+	signedIDToken, err := idToken.SignedString(m.key)
+	if err != nil {
+		writeMockError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	accessToken, err := randomMockValue()
+	if err != nil {
+		writeMockError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	writeMockJson(w, http.StatusOK, Token{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		IDToken:     signedIDToken,
+	})
+}
+
+func (m *MockProvider) authenticateClient(r *http.Request) bool {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+	}
+
+	if clientID != m.clientID {
+		return false
+	}
+
+	return m.clientSecret == "" || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(m.clientSecret)) == 1
+}
+
+func (m *MockProvider) jwks(w http.ResponseWriter, r *http.Request, issuer string) {
+	writeMockJson(w, http.StatusOK, map[string]interface{}{
+		"keys": []jwk{
+			{
+				Kty: "RSA",
+				Kid: mockKeyID,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+			},
+		},
+	})
+}
+
+func randomMockValue() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func writeMockJson(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeMockError(w http.ResponseWriter, status int, code string) {
+	writeMockJson(w, status, map[string]string{
+		"error": code,
+	})
+}
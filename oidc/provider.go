@@ -0,0 +1,237 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("Invalid id token")
+	ErrInvalidNonce   = errors.New("Invalid id token nonce")
+)
+
+type Config struct {
+	Name         string
+	IssuerUrl    string
+	ClientID     string
+	ClientSecret string
+	RedirectUrl  string
+	Scopes       []string
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+}
+
+type Claims struct {
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+	Nonce         string   `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+type Provider struct {
+	config     Config
+	httpClient *http.Client
+
+	mutex     sync.Mutex
+	discovery *discovery
+	keySet    *remoteKeySet
+}
+
+func NewProvider(config Config, httpClient *http.Client) *Provider {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{
+		config:     config,
+		httpClient: httpClient,
+	}
+}
+
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.config.IssuerUrl, "/") + "/.well-known/openid-configuration"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery returned %s", response.Status)
+	}
+
+	var result discovery
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(result.Issuer, "/") != strings.TrimSuffix(p.config.IssuerUrl, "/") {
+		return nil, fmt.Errorf("oidc issuer mismatch: got %s", result.Issuer)
+	}
+
+	p.discovery = &result
+	p.keySet = newRemoteKeySet(result.JwksUri, p.httpClient)
+
+	return p.discovery, nil
+}
+
+func (p *Provider) AuthCodeUrl(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	values.Set("response_type", "code")
+	values.Set("client_id", p.config.ClientID)
+	values.Set("redirect_uri", p.config.RedirectUrl)
+	values.Set("scope", strings.Join(p.config.Scopes, " "))
+	values.Set("state", state)
+	values.Set("nonce", nonce)
+	values.Set("code_challenge", codeChallenge)
+	values.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + values.Encode(), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string) (Token, error) {
+	var token Token
+
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return token, err
+	}
+
+	values := url.Values{}
+	values.Set("grant_type", "authorization_code")
+	values.Set("code", code)
+	values.Set("redirect_uri", p.config.RedirectUrl)
+	values.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret == "" {
+		values.Set("client_id", p.config.ClientID)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return token, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return token, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return token, fmt.Errorf("oidc token endpoint returned %s", response.Status)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return token, err
+	}
+
+	if token.IDToken == "" {
+		return token, ErrInvalidIDToken
+	}
+
+	return token, nil
+}
+
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken string, nonce string) (Claims, error) {
+	var claims Claims
+
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return claims, err
+	}
+
+	_, err = jwt.ParseWithClaims(
+		rawIDToken,
+		&claims,
+		func(token *jwt.Token) (interface{}, error) {
+			keyID, _ := token.Header["kid"].(string)
+			return p.keySet.get(ctx, keyID)
+		},
+		jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+	)
+	if err != nil {
+		return claims, ErrInvalidIDToken
+	}
+
+	if claims.Subject == "" || claims.ExpiresAt == nil {
+		return claims, ErrInvalidIDToken
+	}
+
+	if claims.Nonce != nonce {
+		return claims, ErrInvalidNonce
+	}
+
+	return claims, nil
+}
+
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	default:
+		*b = false
+	}
+	return nil
+}
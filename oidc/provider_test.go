@@ -0,0 +1,149 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newMockProviderServer(t *testing.T, user MockUser) (*httptest.Server, *Provider) {
+	t.Helper()
+
+	mock, err := NewMockProvider("ecommerce-api", "secret", user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+
+	provider := NewProvider(Config{
+		Name:         "mock",
+		IssuerUrl:    server.URL + "/default",
+		ClientID:     "ecommerce-api",
+		ClientSecret: "secret",
+		RedirectUrl:  "http://localhost:8080/auth/oidc/mock/callback",
+	}, server.Client())
+
+	return server, provider
+}
+
+func authorizeMock(t *testing.T, server *httptest.Server, provider *Provider, state string, nonce string, codeVerifier string) string {
+	t.Helper()
+
+	authUrl, err := provider.AuthCodeUrl(context.Background(), state, nonce, CodeChallengeS256(codeVerifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := *server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	response, err := client.Get(authUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		t.Fatalf("authorize returned %s", response.Status)
+	}
+
+	location, err := url.Parse(response.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if location.Query().Get("state") != state {
+		t.Fatalf("state = %q, want %q", location.Query().Get("state"), state)
+	}
+
+	return location.Query().Get("code")
+}
+
+func TestProviderExchangesCodeWithPkce(t *testing.T) {
+	server, provider := newMockProviderServer(t, MockUser{
+		Subject:       "subject-1",
+		Email:         "Alice@Example.com",
+		EmailVerified: true,
+		GivenName:     "Alice",
+		FamilyName:    "Liddell",
+	})
+
+	codeVerifier, err := GenerateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := authorizeMock(t, server, provider, "state-1", "nonce-1", codeVerifier)
+
+	token, err := provider.Exchange(context.Background(), code, codeVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := provider.VerifyIDToken(context.Background(), token.IDToken, "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Subject != "subject-1" || claims.Email != "Alice@Example.com" || !bool(claims.EmailVerified) {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+
+	if _, err := provider.Exchange(context.Background(), code, codeVerifier); err == nil {
+		t.Fatal("authorization code was accepted twice")
+	}
+}
+
+func TestProviderRejectsWrongCodeVerifier(t *testing.T) {
+	server, provider := newMockProviderServer(t, MockUser{
+		Subject:       "subject-1",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+	})
+
+	codeVerifier, err := GenerateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherVerifier, err := GenerateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := authorizeMock(t, server, provider, "state-1", "nonce-1", codeVerifier)
+
+	if _, err := provider.Exchange(context.Background(), code, otherVerifier); err == nil {
+		t.Fatal("exchange succeeded with the wrong code verifier")
+	}
+}
+
+func TestProviderRejectsNonceMismatch(t *testing.T) {
+	server, provider := newMockProviderServer(t, MockUser{
+		Subject:       "subject-1",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+	})
+
+	codeVerifier, err := GenerateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := authorizeMock(t, server, provider, "state-1", "nonce-1", codeVerifier)
+
+	token, err := provider.Exchange(context.Background(), code, codeVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.VerifyIDToken(context.Background(), token.IDToken, "nonce-2"); err != ErrInvalidNonce {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidNonce)
+	}
+}
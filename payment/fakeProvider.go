@@ -0,0 +1,89 @@
+package payment
+
+import (
+	"context"
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"errors"
+	"log"
+)
+
+var ErrIntentNotPending = errors.New("Payment intent is not pending")
+
+type FakeProvider struct {
+	database *database.Database
+}
+
+func NewFakeProvider(database *database.Database) *FakeProvider {
+	return &FakeProvider{
+		database: database,
+	}
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, charge Charge) (Intent, error) {
+	token, err := helper.GenerateRandomToken()
+	if err != nil {
+		return Intent{}, err
+	}
+
+	fakeIntent := model.FakePaymentIntent{
+		Reference: "fake_" + token[:24],
+		TopUpID:   charge.TopUpID,
+		UserEmail: charge.UserEmail,
+		Amount:    charge.Amount,
+		Currency:  charge.Currency,
+	}
+	if err := fakeIntent.CreateOrGetByTopUpID(p.database.Conn); err != nil {
+		return Intent{}, err
+	}
+
+	log.Printf("fake payment %s: %d %s from %s for top-up %s is %s", fakeIntent.Reference, fakeIntent.Amount, fakeIntent.Currency, fakeIntent.UserEmail, fakeIntent.TopUpID, fakeIntent.Status)
+
+	return Intent{
+		Reference: fakeIntent.Reference,
+		Status:    fakeIntent.Status,
+	}, nil
+}
+
+func (p *FakeProvider) GetIntent(ctx context.Context, reference string) (Intent, error) {
+	fakeIntent := model.FakePaymentIntent{
+		Reference: reference,
+	}
+	if err := fakeIntent.GetByReference(p.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return Intent{}, ErrIntentNotFound
+		}
+		return Intent{}, err
+	}
+
+	return Intent{
+		Reference: fakeIntent.Reference,
+		Status:    fakeIntent.Status,
+	}, nil
+}
+
+func (p *FakeProvider) Settle(ctx context.Context, reference string, status string) (Intent, error) {
+	if status != StatusSucceeded && status != StatusFailed {
+		return Intent{}, errors.New("status must be succeeded or failed")
+	}
+
+	if _, err := p.GetIntent(ctx, reference); err != nil {
+		return Intent{}, err
+	}
+
+	fakeIntent := model.FakePaymentIntent{
+		Reference: reference,
+	}
+	if err := fakeIntent.Settle(p.database.Conn, status); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return Intent{}, ErrIntentNotPending
+		}
+		return Intent{}, err
+	}
+
+	return Intent{
+		Reference: fakeIntent.Reference,
+		Status:    fakeIntent.Status,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package payment
+
+import (
+	"context"
+	"ecommerce-api/database"
+	"errors"
+	"fmt"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+var ErrIntentNotFound = errors.New("Payment intent not found")
+
+type Config struct {
+	Driver    string
+	AllowFake bool
+}
+
+type Charge struct {
+	TopUpID   string
+	UserEmail string
+	Amount    int64
+	Currency  string
+}
+
+type Intent struct {
+	Reference   string
+	Status      string
+	CheckoutUrl string
+}
+
+type Provider interface {
+	CreateIntent(ctx context.Context, charge Charge) (Intent, error)
+	GetIntent(ctx context.Context, reference string) (Intent, error)
+}
+
+func NewProvider(config Config, database *database.Database) (Provider, error) {
+	switch config.Driver {
+	case "":
+		return nil, errors.New("PAYMENT_PROVIDER is not set")
+	case "fake":
+		if !config.AllowFake {
+			return nil, errors.New("the fake payment provider settles nothing and needs PAYMENT_ALLOW_FAKE=true")
+		}
+		return NewFakeProvider(database), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", config.Driver)
+	}
+}
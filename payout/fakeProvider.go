@@ -0,0 +1,40 @@
+package payout
+
+import (
+	"context"
+	"ecommerce-api/helper"
+	"log"
+	"sync"
+)
+
+type FakeProvider struct {
+	mutex      sync.Mutex
+	references map[string]string
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		references: map[string]string{},
+	}
+}
+
+func (p *FakeProvider) Send(ctx context.Context, payout Payout) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if reference, ok := p.references[payout.IdempotencyKey]; ok {
+		return reference, nil
+	}
+
+	token, err := helper.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	reference := "fake_" + token[:24]
+	p.references[payout.IdempotencyKey] = reference
+
+	log.Printf("fake payout %s: sent %d to %s for withdrawal %s", reference, payout.Amount, payout.Recipient, payout.WithdrawalID)
+
+	return reference, nil
+}
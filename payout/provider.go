@@ -0,0 +1,27 @@
+package payout
+
+import (
+	"context"
+	"fmt"
+)
+
+type Payout struct {
+	WithdrawalID   string
+	IdempotencyKey string
+	StoreID        string
+	Recipient      string
+	Amount         int64
+}
+
+type Provider interface {
+	Send(ctx context.Context, payout Payout) (string, error)
+}
+
+func NewProvider(driver string) (Provider, error) {
+	switch driver {
+	case "fake":
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown payout provider %q", driver)
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"errors"
+)
+
+var (
+	ErrAddressNotFound    = errors.New("Address not found")
+	ErrNotShippingAddress = errors.New("Address is not a shipping address")
+)
+
+type AddressService struct {
+	database *database.Database
+}
+
+func NewAddressService(database *database.Database) *AddressService {
+	return &AddressService{
+		database: database,
+	}
+}
+
+func (s *AddressService) GetAll(email string) ([]model.Address, error) {
+	return model.GetAllAddressByUserEmail(s.database.Conn, email)
+}
+
+func (s *AddressService) Get(email string, id string) (model.Address, error) {
+	address := model.Address{
+		ID:        id,
+		UserEmail: email,
+	}
+	if err := address.GetByIDAndUserEmail(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return address, ErrAddressNotFound
+		}
+		return address, err
+	}
+
+	return address, nil
+}
+
+func (s *AddressService) Create(email string, saveRequest model.AddressSave) (model.Address, error) {
+	address := saveRequest.ToAddress()
+	address.UserEmail = email
+	address.PostalCode = helper.NormalizePostalCode(address.PostalCode)
+
+	if err := helper.ValidatePostalCode(address.Country, address.PostalCode); err != nil {
+		return address, err
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return address, err
+	}
+
+	user := model.User{
+		Email: email,
+	}
+	if err := user.GetByEmailForUpdate(tx); err != nil {
+		tx.Rollback()
+		return address, err
+	}
+
+	count, err := model.CountAddressByUserEmailAndType(tx, email, address.Type)
+	if err != nil {
+		tx.Rollback()
+		return address, err
+	}
+
+	if count == 0 {
+		address.IsDefault = true
+	}
+
+	if address.IsDefault {
+		if err := model.ClearDefaultAddressByUserEmailAndType(tx, email, address.Type); err != nil {
+			tx.Rollback()
+			return address, err
+		}
+	}
+
+	if err := address.Create(tx); err != nil {
+		tx.Rollback()
+		return address, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return address, err
+	}
+
+	return address, nil
+}
+
+func (s *AddressService) Update(email string, id string, saveRequest model.AddressSave) (model.Address, error) {
+	address := saveRequest.ToAddress()
+	address.ID = id
+	address.UserEmail = email
+	address.PostalCode = helper.NormalizePostalCode(address.PostalCode)
+
+	if err := helper.ValidatePostalCode(address.Country, address.PostalCode); err != nil {
+		return address, err
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return address, err
+	}
+
+	user := model.User{
+		Email: email,
+	}
+	if err := user.GetByEmailForUpdate(tx); err != nil {
+		tx.Rollback()
+		return address, err
+	}
+
+	existing := model.Address{
+		ID:        id,
+		UserEmail: email,
+	}
+	if err := existing.GetByIDAndUserEmail(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return address, ErrAddressNotFound
+		}
+		return address, err
+	}
+
+	if address.IsDefault {
+		if err := model.ClearDefaultAddressByUserEmailAndType(tx, email, address.Type); err != nil {
+			tx.Rollback()
+			return address, err
+		}
+	}
+
+	if err := address.UpdateByIDAndUserEmail(tx); err != nil {
+		tx.Rollback()
+		return address, err
+	}
+
+	for _, addressType := range []string{existing.Type, address.Type} {
+		if err := model.EnsureDefaultAddressByUserEmailAndType(tx, email, addressType, address.ID); err != nil {
+			tx.Rollback()
+			return address, err
+		}
+	}
+
+	if err := address.GetByIDAndUserEmail(tx); err != nil {
+		tx.Rollback()
+		return address, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return address, err
+	}
+
+	return address, nil
+}
+
+func (s *AddressService) Delete(email string, id string) error {
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	user := model.User{
+		Email: email,
+	}
+	if err := user.GetByEmailForUpdate(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	address := model.Address{
+		ID:        id,
+		UserEmail: email,
+	}
+	if err := address.DeleteByIDAndUserEmail(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return ErrAddressNotFound
+		}
+		return err
+	}
+
+	if err := model.EnsureDefaultAddressByUserEmailAndType(tx, email, address.Type, address.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *AddressService) snapshot(dbConn model.DBConn, email string, id string) (*model.AddressSnapshot, error) {
+	address := model.Address{
+		ID:        id,
+		UserEmail: email,
+		Type:      model.AddressTypeShipping,
+	}
+
+	if id == "" {
+		if err := address.GetDefaultByUserEmailAndType(dbConn); err != nil {
+			if err.Error() == "sql: no rows in result set" {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return address.ToSnapshot(), nil
+	}
+
+	if err := address.GetByIDAndUserEmail(dbConn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, ErrAddressNotFound
+		}
+		return nil, err
+	}
+
+	if address.Type != model.AddressTypeShipping {
+		return nil, ErrNotShippingAddress
+	}
+
+	return address.ToSnapshot(), nil
+}
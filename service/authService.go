@@ -1,32 +1,62 @@
 package service
 
 import (
+	"ecommerce-api/bruteforce"
 	"ecommerce-api/database"
+	"ecommerce-api/denylist"
 	"ecommerce-api/helper"
 	"ecommerce-api/model"
 	"errors"
+	"log"
 
 	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var ErrInvalidEmailOrPassword = errors.New("Invalid email or password")
+var (
+	ErrInvalidEmailOrPassword = errors.New("Invalid email or password")
+	ErrInvalidRefreshToken    = errors.New("Invalid refresh token")
+	ErrRefreshTokenExpired    = errors.New("Refresh token expired")
+	ErrRefreshTokenReused     = errors.New("Refresh token reused")
+
+	ErrInvalidTwoFactorChallenge = errors.New("Invalid or expired two-factor challenge")
+	ErrUserBanned                = errors.New("User is banned")
+)
+
+type LoginProtection struct {
+	Guard       *bruteforce.Guard
+	EmailPolicy bruteforce.Policy
+	IpPolicy    bruteforce.Policy
+}
 
 type AuthService struct {
-	database *database.Database
-	jwtKey   []byte
+	database         *database.Database
+	twoFactorService *TwoFactorService
+	jwtKeySet        *helper.JwtKeySet
+	denylist         *denylist.Denylist
+	loginProtection  LoginProtection
 }
 
-func NewAuthService(database *database.Database, jwtKey []byte) *AuthService {
+func NewAuthService(
+	database *database.Database,
+	twoFactorService *TwoFactorService,
+	jwtKeySet *helper.JwtKeySet,
+	denylist *denylist.Denylist,
+	loginProtection LoginProtection,
+) *AuthService {
 	return &AuthService{
-		database: database,
-		jwtKey:   jwtKey,
+		database:         database,
+		twoFactorService: twoFactorService,
+		jwtKeySet:        jwtKeySet,
+		denylist:         denylist,
+		loginProtection:  loginProtection,
 	}
 }
 
-func (s *AuthService) CurrentUser(c echo.Context) (model.User, error) {
+func (s *AuthService) CurrentUser(principal helper.Principal) (model.User, error) {
 	user := model.User{
-		Email: helper.ExtractJwtEmail(c),
+		Email: principal.Email,
 	}
 
 	if err := user.GetByEmail(s.database.Conn); err != nil {
@@ -36,35 +66,376 @@ func (s *AuthService) CurrentUser(c echo.Context) (model.User, error) {
 	return user, nil
 }
 
-func (s *AuthService) Login(loginRequest model.UserLogin, c echo.Context) (string, error) {
-	var accessToken string
+func (s *AuthService) Login(loginRequest model.UserLogin, c echo.Context) (model.UserLoginResult, error) {
+	var result model.UserLoginResult
+
+	emailKey := loginEmailKey(loginRequest.Email)
+	ipKey := "ip:" + c.RealIP()
+	if err := s.loginProtection.Guard.Check(emailKey, ipKey); err != nil {
+		return result, err
+	}
 
 	password := loginRequest.Password
 	user := loginRequest.ToUser()
 	if err := user.GetByEmail(s.database.Conn); err != nil {
 		if err.Error() == "sql: no rows in result set" {
-			return accessToken, errors.New("Invalid email or password")
+			s.recordLoginFailure(emailKey, s.loginProtection.EmailPolicy)
+			s.recordLoginFailure(ipKey, s.loginProtection.IpPolicy)
+			return result, ErrInvalidEmailOrPassword
 		}
-		return accessToken, err
+		return result, err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return accessToken, errors.New("Invalid email or password")
+		s.recordLoginFailure(emailKey, s.loginProtection.EmailPolicy)
+		s.recordLoginFailure(ipKey, s.loginProtection.IpPolicy)
+		return result, ErrInvalidEmailOrPassword
+	}
+
+	if err := s.loginProtection.Guard.Reset(emailKey); err != nil {
+		return result, err
+	}
+
+	return s.completeLogin(user, c)
+}
+
+func (s *AuthService) GetAllActiveLockout() ([]model.LoginLockout, error) {
+	return model.GetAllActiveLoginLockout(s.database.Conn)
+}
+
+func (s *AuthService) Unlock(email string, unlockedBy string) error {
+	emailKey := loginEmailKey(email)
+	if err := s.loginProtection.Guard.Reset(emailKey); err != nil {
+		return err
+	}
+
+	return model.UnlockLoginLockoutsByKey(s.database.Conn, emailKey, unlockedBy)
+}
+
+func (s *AuthService) recordLoginFailure(key string, policy bruteforce.Policy) {
+	attempt, lockedOut, err := s.loginProtection.Guard.Fail(key, policy)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	if !lockedOut {
+		return
+	}
+
+	lockout := model.LoginLockout{
+		Key:         key,
+		Failures:    attempt.Failures,
+		LockedUntil: attempt.BlockedUntil,
+	}
+	if err := lockout.Create(s.database.Conn); err != nil {
+		log.Println(err)
+	}
+}
+
+func loginEmailKey(email string) string {
+	return "email:" + helper.NormalizeEmail(email)
+}
+
+func (s *AuthService) completeLogin(user model.User, c echo.Context) (model.UserLoginResult, error) {
+	var result model.UserLoginResult
+
+	if user.IsBanned() {
+		return result, ErrUserBanned
+	}
+
+	twoFactorEnabled, err := s.twoFactorService.IsEnabled(user.Email)
+	if err != nil {
+		return result, err
+	}
+
+	if twoFactorEnabled {
+		challengeToken, err := helper.GenerateRandomToken()
+		if err != nil {
+			return result, err
+		}
+
+		challenge := model.TwoFactorChallenge{
+			TokenHash: helper.HashToken(challengeToken),
+			UserEmail: user.Email,
+		}
+		if err := challenge.Create(s.database.Conn); err != nil {
+			return result, err
+		}
+
+		result.ChallengeToken = challengeToken
+		result.TwoFactorRequired = true
+		return result, nil
+	}
+
+	result.AccessToken, err = s.issueTokenPair(user, c)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (s *AuthService) VerifyTwoFactor(verifyRequest model.TwoFactorVerify, c echo.Context) (model.UserLoginResult, error) {
+	var result model.UserLoginResult
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return result, err
+	}
+
+	challenge := model.TwoFactorChallenge{
+		TokenHash: helper.HashToken(verifyRequest.ChallengeToken),
+	}
+	if err := challenge.GetByTokenHashForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return result, ErrInvalidTwoFactorChallenge
+		}
+		return result, err
+	}
+
+	if challenge.IsUsed() || challenge.IsExpired() {
+		tx.Rollback()
+		return result, ErrInvalidTwoFactorChallenge
+	}
+
+	emailKey := loginEmailKey(challenge.UserEmail)
+	if err := s.loginProtection.Guard.Check(emailKey); err != nil {
+		tx.Rollback()
+		return result, err
+	}
+
+	if err := s.twoFactorService.verifyCode(tx, challenge.UserEmail, verifyRequest.Code); err != nil {
+		tx.Rollback()
+		if err == ErrInvalidTwoFactorCode {
+			s.recordLoginFailure(emailKey, s.loginProtection.EmailPolicy)
+		}
+		return result, err
+	}
+
+	if err := challenge.MarkUsed(tx); err != nil {
+		tx.Rollback()
+		return result, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, err
+	}
+
+	user := model.User{
+		Email: challenge.UserEmail,
+	}
+	if err := user.GetByEmail(s.database.Conn); err != nil {
+		return result, err
+	}
+
+	if user.IsBanned() {
+		return result, ErrUserBanned
+	}
+
+	result.AccessToken, err = s.issueTokenPair(user, c)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (s *AuthService) issueTokenPair(user model.User, c echo.Context) (string, error) {
+	var accessToken string
+
+	if err := model.DeleteExpiredRefreshTokens(s.database.Conn); err != nil {
+		return accessToken, err
+	}
+
+	if err := model.DeleteStaleSessions(s.database.Conn); err != nil {
+		return accessToken, err
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return accessToken, err
+	}
+
+	session := model.Session{
+		UserEmail: user.Email,
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	}
+	if err := session.Create(tx); err != nil {
+		tx.Rollback()
+		return accessToken, err
 	}
 
 	refreshToken := model.RefreshToken{
 		UserEmail: user.Email,
+		FamilyID:  session.ID,
 	}
-	if err := refreshToken.Create(s.database.Conn); err != nil {
+	if err := refreshToken.Create(tx); err != nil {
+		tx.Rollback()
+		return accessToken, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return accessToken, err
 	}
 
 	helper.AssignRefreshTokenCookes(refreshToken.Token, c)
 
-	accessToken, err := helper.GenerateJwtToken(user.Email, s.jwtKey)
+	accessToken, err = helper.GenerateJwtToken(user.Email, user.Roles, session.ID, s.jwtKeySet)
+	if err != nil {
+		return accessToken, err
+	}
+
+	return accessToken, nil
+}
+
+func (s *AuthService) Refresh(token string, c echo.Context) (string, error) {
+	var accessToken string
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return accessToken, err
+	}
+
+	refreshToken := model.RefreshToken{
+		Token: token,
+	}
+	if err := refreshToken.GetByTokenForUpdate(tx); err != nil {
+		tx.Rollback()
+		if isInvalidRefreshTokenError(err) {
+			return accessToken, ErrInvalidRefreshToken
+		}
+		return accessToken, err
+	}
+
+	if refreshToken.IsRevoked() {
+		if err := model.RevokeRefreshTokenFamily(tx, refreshToken.FamilyID); err != nil {
+			tx.Rollback()
+			return accessToken, err
+		}
+
+		reuseEvent := model.RefreshTokenReuseEvent{
+			Token:     refreshToken.Token,
+			FamilyID:  refreshToken.FamilyID,
+			UserEmail: refreshToken.UserEmail,
+		}
+		if err := reuseEvent.Create(tx); err != nil {
+			tx.Rollback()
+			return accessToken, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return accessToken, err
+		}
+
+		if err := s.denylist.RevokeSessions(refreshToken.FamilyID); err != nil {
+			return accessToken, err
+		}
+		return accessToken, ErrRefreshTokenReused
+	}
+
+	if refreshToken.IsExpired() {
+		if err := refreshToken.Delete(tx); err != nil {
+			tx.Rollback()
+			return accessToken, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return accessToken, err
+		}
+		return accessToken, ErrRefreshTokenExpired
+	}
+
+	rotatedToken := model.RefreshToken{
+		UserEmail: refreshToken.UserEmail,
+		FamilyID:  refreshToken.FamilyID,
+	}
+	if err := rotatedToken.Create(tx); err != nil {
+		tx.Rollback()
+		return accessToken, err
+	}
+
+	if err := refreshToken.Revoke(tx, rotatedToken.Token); err != nil {
+		tx.Rollback()
+		return accessToken, err
+	}
+
+	session := model.Session{
+		ID:        refreshToken.FamilyID,
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	}
+	if err := session.Touch(tx); err != nil {
+		tx.Rollback()
+		return accessToken, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return accessToken, err
+	}
+
+	helper.AssignRefreshTokenCookes(rotatedToken.Token, c)
+
+	user := model.User{
+		Email: rotatedToken.UserEmail,
+	}
+	if err := user.GetByEmail(s.database.Conn); err != nil {
+		return accessToken, err
+	}
+
+	if user.IsBanned() {
+		return accessToken, ErrUserBanned
+	}
+
+	accessToken, err = helper.GenerateJwtToken(user.Email, user.Roles, refreshToken.FamilyID, s.jwtKeySet)
 	if err != nil {
 		return accessToken, err
 	}
 
 	return accessToken, nil
 }
+
+func (s *AuthService) Logout(token string, principal helper.Principal) error {
+	if err := s.denylist.RevokeToken(principal.TokenID); err != nil {
+		return err
+	}
+
+	if err := s.denylist.RevokeSessions(principal.SessionID); err != nil {
+		return err
+	}
+
+	if token == "" {
+		return nil
+	}
+
+	refreshToken := model.RefreshToken{
+		Token: token,
+	}
+	if err := refreshToken.GetByToken(s.database.Conn); err != nil {
+		if isInvalidRefreshTokenError(err) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+
+	if err := s.denylist.RevokeSessions(refreshToken.FamilyID); err != nil {
+		return err
+	}
+
+	session := model.Session{
+		ID: refreshToken.FamilyID,
+	}
+
+	return session.Delete(s.database.Conn)
+}
+
+func isInvalidRefreshTokenError(err error) bool {
+	if err.Error() == "sql: no rows in result set" {
+		return true
+	}
+
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code.Class() == "22"
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/storage"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrAvatarTooLarge        = errors.New("Avatar is too large")
+	ErrUnsupportedAvatarType = errors.New("Unsupported avatar type")
+	ErrInvalidAvatar         = errors.New("Invalid avatar image")
+)
+
+var (
+	avatarSizes       = []int{64, 256, 512}
+	avatarDefaultSize = 256
+	avatarTypes       = map[string]bool{
+		"image/gif":  true,
+		"image/jpeg": true,
+		"image/png":  true,
+	}
+)
+
+type AvatarPolicy struct {
+	MaxSize      int64
+	MaxDimension int
+}
+
+type AvatarService struct {
+	database *database.Database
+	storage  storage.Storage
+	policy   AvatarPolicy
+}
+
+func NewAvatarService(database *database.Database, storage storage.Storage, policy AvatarPolicy) *AvatarService {
+	return &AvatarService{
+		database: database,
+		storage:  storage,
+		policy:   policy,
+	}
+}
+
+func (s *AvatarService) Upload(ctx context.Context, email string, body io.Reader, contentType string) (model.User, error) {
+	user := model.User{
+		Email: email,
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, s.policy.MaxSize+1))
+	if err != nil {
+		return user, err
+	}
+
+	if int64(len(data)) > s.policy.MaxSize {
+		return user, ErrAvatarTooLarge
+	}
+
+	if contentType != "" && !avatarTypes[strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))] {
+		return user, ErrUnsupportedAvatarType
+	}
+
+	if !avatarTypes[http.DetectContentType(data)] {
+		return user, ErrUnsupportedAvatarType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return user, ErrInvalidAvatar
+	}
+
+	if config.Width > s.policy.MaxDimension || config.Height > s.policy.MaxDimension {
+		return user, ErrAvatarTooLarge
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return user, ErrInvalidAvatar
+	}
+
+	token, err := helper.GenerateRandomToken()
+	if err != nil {
+		return user, err
+	}
+
+	avatar := &model.Avatar{
+		Key:      "avatars/" + token[:32],
+		Variants: map[string]string{},
+	}
+
+	for _, size := range avatarSizes {
+		var encoded bytes.Buffer
+		if err := jpeg.Encode(&encoded, helper.ResizeSquare(source, size), &jpeg.Options{Quality: 85}); err != nil {
+			s.purge(ctx, avatar)
+			return user, err
+		}
+
+		key := avatarVariantKey(avatar.Key, strconv.Itoa(size))
+		if err := s.storage.Put(ctx, key, encoded.Bytes(), "image/jpeg"); err != nil {
+			s.purge(ctx, avatar)
+			return user, err
+		}
+
+		avatar.Variants[strconv.Itoa(size)] = s.storage.URL(key)
+	}
+	avatar.URL = avatar.Variants[strconv.Itoa(avatarDefaultSize)]
+
+	if err := user.GetByEmail(s.database.Conn); err != nil {
+		s.purge(ctx, avatar)
+		return user, err
+	}
+	previous := user.Avatar
+
+	user.Avatar = avatar
+	if err := user.UpdateAvatar(s.database.Conn); err != nil {
+		s.purge(ctx, avatar)
+		return user, err
+	}
+
+	s.purge(ctx, previous)
+
+	return user, nil
+}
+
+func (s *AvatarService) Delete(ctx context.Context, email string) (model.User, error) {
+	user := model.User{
+		Email: email,
+	}
+	if err := user.GetByEmail(s.database.Conn); err != nil {
+		return user, err
+	}
+	previous := user.Avatar
+
+	user.Avatar = nil
+	if err := user.UpdateAvatar(s.database.Conn); err != nil {
+		return user, err
+	}
+
+	s.purge(ctx, previous)
+
+	return user, nil
+}
+
+func (s *AvatarService) purge(ctx context.Context, avatar *model.Avatar) {
+	if avatar == nil {
+		return
+	}
+
+	for variant := range avatar.Variants {
+		if err := s.storage.Delete(ctx, avatarVariantKey(avatar.Key, variant)); err != nil && err != storage.ErrObjectNotFound {
+			log.Println(err)
+		}
+	}
+}
+
+func avatarVariantKey(key string, variant string) string {
+	return key + "/" + variant + ".jpg"
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/model"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrCategoryNotFound       = errors.New("Category not found")
+	ErrParentCategoryNotFound = errors.New("Parent category not found")
+	ErrCategoryCycle          = errors.New("Category cannot be moved under itself")
+	ErrCategoryHasChildren    = errors.New("Category has subcategories")
+	ErrCategoryNameTaken      = errors.New("Category name already taken")
+)
+
+type CategoryService struct {
+	database *database.Database
+}
+
+func NewCategoryService(database *database.Database) *CategoryService {
+	return &CategoryService{
+		database: database,
+	}
+}
+
+func (s *CategoryService) GetTree() ([]model.Category, error) {
+	categories, err := model.GetAllCategory(s.database.Conn)
+	if err != nil {
+		return nil, err
+	}
+
+	childrenByParent := map[string][]model.Category{}
+	for _, category := range categories {
+		parentID := ""
+		if category.ParentID != nil {
+			parentID = *category.ParentID
+		}
+		childrenByParent[parentID] = append(childrenByParent[parentID], category)
+	}
+
+	return buildCategoryTree(childrenByParent, ""), nil
+}
+
+func buildCategoryTree(childrenByParent map[string][]model.Category, parentID string) []model.Category {
+	tree := []model.Category{}
+	for _, category := range childrenByParent[parentID] {
+		category.Children = buildCategoryTree(childrenByParent, category.ID)
+		tree = append(tree, category)
+	}
+
+	return tree
+}
+
+func (s *CategoryService) Get(id string) (model.Category, error) {
+	return s.get(s.database.Conn, id)
+}
+
+func (s *CategoryService) Create(saveRequest model.CategorySave) (model.Category, error) {
+	category := saveRequest.ToCategory()
+
+	if category.ParentID != nil {
+		if _, err := s.get(s.database.Conn, *category.ParentID); err != nil {
+			if err == ErrCategoryNotFound {
+				return category, ErrParentCategoryNotFound
+			}
+			return category, err
+		}
+	}
+
+	if err := category.Create(s.database.Conn); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return category, ErrCategoryNameTaken
+		}
+		return category, err
+	}
+
+	return category, nil
+}
+
+func (s *CategoryService) Update(id string, saveRequest model.CategorySave) (model.Category, error) {
+	category := saveRequest.ToCategory()
+	category.ID = id
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return category, err
+	}
+
+	if err := model.LockCategoryTree(tx); err != nil {
+		tx.Rollback()
+		return category, err
+	}
+
+	if _, err := s.get(tx, id); err != nil {
+		tx.Rollback()
+		return category, err
+	}
+
+	if category.ParentID != nil {
+		if _, err := s.get(tx, *category.ParentID); err != nil {
+			tx.Rollback()
+			if err == ErrCategoryNotFound {
+				return category, ErrParentCategoryNotFound
+			}
+			return category, err
+		}
+
+		descendant, err := model.IsCategoryDescendant(tx, id, *category.ParentID)
+		if err != nil {
+			tx.Rollback()
+			return category, err
+		}
+
+		if descendant {
+			tx.Rollback()
+			return category, ErrCategoryCycle
+		}
+	}
+
+	if err := category.UpdateByID(tx); err != nil {
+		tx.Rollback()
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return category, ErrCategoryNameTaken
+		}
+		return category, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return category, err
+	}
+
+	return category, nil
+}
+
+func (s *CategoryService) Delete(id string) error {
+	category := model.Category{
+		ID: id,
+	}
+	if err := category.Delete(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return ErrCategoryNotFound
+		}
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return ErrCategoryHasChildren
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *CategoryService) get(dbConn model.DBConn, id string) (model.Category, error) {
+	category := model.Category{
+		ID: id,
+	}
+	if err := category.GetByID(dbConn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return category, ErrCategoryNotFound
+		}
+		return category, err
+	}
+
+	return category, nil
+}
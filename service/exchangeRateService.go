@@ -0,0 +1,98 @@
+package service
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/model"
+	"ecommerce-api/money"
+	"errors"
+	"math/big"
+)
+
+var ErrExchangeRateNotFound = errors.New("Exchange rate not found")
+
+type ExchangeRateService struct {
+	database *database.Database
+}
+
+func NewExchangeRateService(database *database.Database) *ExchangeRateService {
+	return &ExchangeRateService{
+		database: database,
+	}
+}
+
+func (s *ExchangeRateService) GetAll() ([]model.ExchangeRate, error) {
+	return model.GetAllExchangeRate(s.database.Conn)
+}
+
+func (s *ExchangeRateService) Set(updateRequest model.ExchangeRateUpdate, updatedBy string) (model.ExchangeRate, error) {
+	exchangeRate := updateRequest.ToExchangeRate()
+	exchangeRate.UpdatedBy = updatedBy
+
+	if _, err := money.ParseRate(exchangeRate.Rate); err != nil {
+		return exchangeRate, err
+	}
+
+	if err := exchangeRate.Upsert(s.database.Conn); err != nil {
+		return exchangeRate, err
+	}
+
+	return exchangeRate, nil
+}
+
+func (s *ExchangeRateService) Delete(baseCurrency string, quoteCurrency string) error {
+	exchangeRate := model.ExchangeRate{
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+	}
+	if err := exchangeRate.Delete(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return ErrExchangeRateNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *ExchangeRateService) convert(dbConn model.DBConn, amount money.Money, currency string) (money.Money, string, error) {
+	rate, err := s.rate(dbConn, amount.Currency, currency)
+	if err != nil {
+		return amount, "", err
+	}
+
+	return money.Convert(amount, currency, rate), rate.RatString(), nil
+}
+
+func (s *ExchangeRateService) rate(dbConn model.DBConn, baseCurrency string, quoteCurrency string) (*big.Rat, error) {
+	if baseCurrency == quoteCurrency {
+		return big.NewRat(1, 1), nil
+	}
+
+	exchangeRate := model.ExchangeRate{
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+	}
+	if err := exchangeRate.GetByCurrencies(dbConn); err == nil {
+		return money.ParseRate(exchangeRate.Rate)
+	} else if err.Error() != "sql: no rows in result set" {
+		return nil, err
+	}
+
+	inverse := model.ExchangeRate{
+		BaseCurrency:  quoteCurrency,
+		QuoteCurrency: baseCurrency,
+	}
+	if err := inverse.GetByCurrencies(dbConn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, ErrExchangeRateNotFound
+		}
+		return nil, err
+	}
+
+	rate, err := money.ParseRate(inverse.Rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rate.Inv(rate), nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/oidc"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrOidcProviderNotFound = errors.New("OIDC provider not found")
+	ErrInvalidOidcState     = errors.New("Invalid or expired OIDC state")
+	ErrOidcLoginFailed      = errors.New("OIDC login failed")
+	ErrOidcEmailNotVerified = errors.New("OIDC email not verified")
+)
+
+type OidcService struct {
+	database    *database.Database
+	authService *AuthService
+	providers   map[string]*oidc.Provider
+}
+
+func NewOidcService(database *database.Database, authService *AuthService, providers []*oidc.Provider) *OidcService {
+	providerMap := map[string]*oidc.Provider{}
+	for _, provider := range providers {
+		providerMap[provider.Name()] = provider
+	}
+
+	return &OidcService{
+		database:    database,
+		authService: authService,
+		providers:   providerMap,
+	}
+}
+
+func (s *OidcService) Begin(ctx context.Context, providerName string, c echo.Context) (string, error) {
+	var authUrl string
+
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return authUrl, ErrOidcProviderNotFound
+	}
+
+	state, err := helper.GenerateRandomToken()
+	if err != nil {
+		return authUrl, err
+	}
+
+	nonce, err := helper.GenerateRandomToken()
+	if err != nil {
+		return authUrl, err
+	}
+
+	codeVerifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return authUrl, err
+	}
+
+	if err := model.DeleteExpiredOidcStates(s.database.Conn); err != nil {
+		return authUrl, err
+	}
+
+	oidcState := model.OidcState{
+		StateHash:    helper.HashToken(state),
+		Provider:     providerName,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+	}
+	if err := oidcState.Create(s.database.Conn); err != nil {
+		return authUrl, err
+	}
+
+	helper.AssignOidcStateCookie(state, c)
+
+	return provider.AuthCodeUrl(ctx, state, nonce, oidc.CodeChallengeS256(codeVerifier))
+}
+
+func (s *OidcService) Callback(providerName string, callbackRequest model.OidcCallback, c echo.Context) (model.UserLoginResult, error) {
+	var result model.UserLoginResult
+
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return result, ErrOidcProviderNotFound
+	}
+
+	stateMatches := helper.OidcStateCookieMatches(callbackRequest.State, c)
+	helper.ClearOidcStateCookie(c)
+	if !stateMatches {
+		return result, ErrInvalidOidcState
+	}
+
+	oidcState := model.OidcState{
+		StateHash: helper.HashToken(callbackRequest.State),
+		Provider:  providerName,
+	}
+	if err := oidcState.Consume(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return result, ErrInvalidOidcState
+		}
+		return result, err
+	}
+
+	if oidcState.IsExpired() {
+		return result, ErrInvalidOidcState
+	}
+
+	ctx := c.Request().Context()
+
+	token, err := provider.Exchange(ctx, callbackRequest.Code, oidcState.CodeVerifier)
+	if err != nil {
+		log.Println(err)
+		return result, ErrOidcLoginFailed
+	}
+
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, oidcState.Nonce)
+	if err != nil {
+		log.Println(err)
+		return result, ErrOidcLoginFailed
+	}
+
+	user, err := s.findOrCreateUser(providerName, claims)
+	if err != nil {
+		return result, err
+	}
+
+	return s.authService.completeLogin(user, c)
+}
+
+func (s *OidcService) findOrCreateUser(providerName string, claims oidc.Claims) (model.User, error) {
+	var user model.User
+
+	identity := model.UserIdentity{
+		Provider: providerName,
+		Subject:  claims.Subject,
+	}
+	if err := identity.GetByProviderAndSubject(s.database.Conn); err == nil {
+		user.Email = identity.UserEmail
+		return user, user.GetByEmail(s.database.Conn)
+	} else if err.Error() != "sql: no rows in result set" {
+		return user, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return user, ErrOidcEmailNotVerified
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return user, err
+	}
+
+	user.Email = helper.NormalizeEmail(claims.Email)
+	if err := user.GetByEmail(tx); err != nil {
+		if err.Error() != "sql: no rows in result set" {
+			tx.Rollback()
+			return user, err
+		}
+
+		if err := s.createUser(tx, &user, claims); err != nil {
+			tx.Rollback()
+			return user, err
+		}
+	}
+
+	if !user.IsEmailVerified() {
+		if err := user.MarkEmailVerified(tx); err != nil {
+			tx.Rollback()
+			return user, err
+		}
+	}
+
+	identity.UserEmail = user.Email
+	if err := identity.Create(tx); err != nil {
+		tx.Rollback()
+		return user, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (s *OidcService) createUser(dbConn model.DBConn, user *model.User, claims oidc.Claims) error {
+	password, err := helper.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.FirstName = claims.GivenName
+	user.LastName = claims.FamilyName
+	if user.FirstName == "" {
+		names := strings.SplitN(strings.TrimSpace(claims.Name), " ", 2)
+		user.FirstName = names[0]
+		if len(names) > 1 {
+			user.LastName = names[1]
+		}
+	}
+	if user.FirstName == "" {
+		user.FirstName = strings.SplitN(user.Email, "@", 2)[0]
+	}
+	user.Password = string(hashedPassword)
+
+	return user.Create(dbConn)
+}
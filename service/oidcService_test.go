@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"ecommerce-api/bruteforce"
+	"ecommerce-api/database"
+	"ecommerce-api/denylist"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/oidc"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func openTestDatabase(t *testing.T) *database.Database {
+	t.Helper()
+
+	databaseUrl := os.Getenv("TEST_DATABASE_URL")
+	if databaseUrl == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", databaseUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemaToken, err := helper.GenerateRandomToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := "test_" + schemaToken[:16]
+
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatal(err)
+	}
+
+	separator := "?"
+	if strings.Contains(databaseUrl, "?") {
+		separator = "&"
+	}
+	testDatabase := database.NewDatabase(databaseUrl + separator + "search_path=" + url.QueryEscape(schema+",public"))
+
+	t.Cleanup(func() {
+		testDatabase.CloseConn()
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		admin.Close()
+	})
+
+	paths, err := filepath.Glob(filepath.Join("..", "database", "migrations", "*.up.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		migration, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := testDatabase.Conn.Exec(string(migration)); err != nil {
+			t.Fatalf("apply %s: %v", filepath.Base(path), err)
+		}
+	}
+
+	return testDatabase
+}
+
+func newTestOidcService(t *testing.T, testDatabase *database.Database, user oidc.MockUser) (*OidcService, *oidc.MockProvider, *httptest.Server) {
+	t.Helper()
+
+	mock, err := oidc.NewMockProvider("ecommerce-api", "secret", user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+
+	provider := oidc.NewProvider(oidc.Config{
+		Name:         "mock",
+		IssuerUrl:    server.URL + "/default",
+		ClientID:     "ecommerce-api",
+		ClientSecret: "secret",
+		RedirectUrl:  "http://localhost:8080/auth/oidc/mock/callback",
+	}, server.Client())
+
+	authService := NewAuthService(
+		testDatabase,
+		NewTwoFactorService(testDatabase, "Test"),
+		helper.NewHmacJwtKeySet([]byte("test-secret")),
+		denylist.NewDenylist(denylist.NewMemoryStore(), helper.AccessTokenLifetime),
+		LoginProtection{
+			Guard: bruteforce.NewGuard(bruteforce.NewMemoryStore()),
+		},
+	)
+
+	return NewOidcService(testDatabase, authService, []*oidc.Provider{provider}), mock, server
+}
+
+func beginMockOidc(t *testing.T, oidcService *OidcService, server *httptest.Server) (*url.URL, []*http.Cookie) {
+	t.Helper()
+
+	beginRecorder := httptest.NewRecorder()
+	beginContext := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/auth/oidc/mock/login", nil), beginRecorder)
+
+	authUrl, err := oidcService.Begin(context.Background(), "mock", beginContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := *server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	response, err := client.Get(authUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	location, err := url.Parse(response.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return location, beginRecorder.Result().Cookies()
+}
+
+func callbackMockOidc(oidcService *OidcService, location *url.URL, cookies []*http.Cookie) (model.UserLoginResult, error) {
+	request := httptest.NewRequest(http.MethodGet, location.String(), nil)
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+	c := echo.New().NewContext(request, httptest.NewRecorder())
+
+	return oidcService.Callback("mock", model.OidcCallback{
+		State: location.Query().Get("state"),
+		Code:  location.Query().Get("code"),
+	}, c)
+}
+
+func loginWithMockOidc(t *testing.T, oidcService *OidcService, server *httptest.Server) (model.UserLoginResult, error) {
+	t.Helper()
+
+	location, cookies := beginMockOidc(t, oidcService, server)
+
+	return callbackMockOidc(oidcService, location, cookies)
+}
+
+func countUsers(t *testing.T, testDatabase *database.Database) int {
+	t.Helper()
+
+	var count int
+	if err := testDatabase.Conn.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+
+	return count
+}
+
+func TestOidcLinksIdentityToExistingUser(t *testing.T) {
+	testDatabase := openTestDatabase(t)
+
+	user := model.User{
+		Email:     "alice@example.com",
+		FirstName: "Alice",
+		LastName:  "Liddell",
+		Password:  "unused",
+	}
+	if err := user.Create(testDatabase.Conn); err != nil {
+		t.Fatal(err)
+	}
+
+	oidcService, mock, server := newTestOidcService(t, testDatabase, oidc.MockUser{
+		Subject:       "subject-alice",
+		Email:         "Alice@Example.com",
+		EmailVerified: true,
+	})
+
+	result, err := loginWithMockOidc(t, oidcService, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.AccessToken == "" {
+		t.Fatal("expected an access token")
+	}
+
+	identity := model.UserIdentity{
+		Provider: "mock",
+		Subject:  "subject-alice",
+	}
+	if err := identity.GetByProviderAndSubject(testDatabase.Conn); err != nil {
+		t.Fatal(err)
+	}
+	if identity.UserEmail != "alice@example.com" {
+		t.Fatalf("identity linked to %q, want %q", identity.UserEmail, "alice@example.com")
+	}
+
+	mock.SetUser(oidc.MockUser{
+		Subject:       "subject-alice",
+		Email:         "alice.changed@example.com",
+		EmailVerified: true,
+	})
+
+	if _, err := loginWithMockOidc(t, oidcService, server); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := countUsers(t, testDatabase); count != 1 {
+		t.Fatalf("users = %d, want 1", count)
+	}
+}
+
+func TestOidcCreatesUserOnFirstLogin(t *testing.T) {
+	testDatabase := openTestDatabase(t)
+
+	oidcService, _, server := newTestOidcService(t, testDatabase, oidc.MockUser{
+		Subject:       "subject-bob",
+		Email:         "Bob@Example.com",
+		EmailVerified: true,
+		GivenName:     "Bob",
+		FamilyName:    "Builder",
+	})
+
+	if _, err := loginWithMockOidc(t, oidcService, server); err != nil {
+		t.Fatal(err)
+	}
+
+	user := model.User{
+		Email: "bob@example.com",
+	}
+	if err := user.GetByEmail(testDatabase.Conn); err != nil {
+		t.Fatal(err)
+	}
+	if !user.IsEmailVerified() || user.FirstName != "Bob" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+}
+
+func TestOidcRejectsUnverifiedEmail(t *testing.T) {
+	testDatabase := openTestDatabase(t)
+
+	oidcService, _, server := newTestOidcService(t, testDatabase, oidc.MockUser{
+		Subject: "subject-eve",
+		Email:   "eve@example.com",
+	})
+
+	if _, err := loginWithMockOidc(t, oidcService, server); err != ErrOidcEmailNotVerified {
+		t.Fatalf("err = %v, want %v", err, ErrOidcEmailNotVerified)
+	}
+
+	if count := countUsers(t, testDatabase); count != 0 {
+		t.Fatalf("users = %d, want 0", count)
+	}
+}
+
+func TestOidcRejectsCallbackFromAnotherBrowser(t *testing.T) {
+	testDatabase := openTestDatabase(t)
+
+	oidcService, _, server := newTestOidcService(t, testDatabase, oidc.MockUser{
+		Subject:       "subject-mallory",
+		Email:         "mallory@example.com",
+		EmailVerified: true,
+	})
+
+	location, _ := beginMockOidc(t, oidcService, server)
+
+	if _, err := callbackMockOidc(oidcService, location, nil); err != ErrInvalidOidcState {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidOidcState)
+	}
+
+	if count := countUsers(t, testDatabase); count != 0 {
+		t.Fatalf("users = %d, want 0", count)
+	}
+}
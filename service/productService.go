@@ -5,8 +5,6 @@ import (
 	"ecommerce-api/helper"
 	"ecommerce-api/model"
 	"errors"
-
-	"github.com/labstack/echo/v4"
 )
 
 var (
@@ -16,23 +14,56 @@ var (
 	ErrBuyYourOwnProduct   = errors.New("Buy your own product")
 	ErrDontHaveStore       = errors.New("Don't have a store")
 	ErrDontOwnProduct      = errors.New("Don't own this product")
+	ErrEmailNotVerified    = errors.New("Email not verified")
+	ErrInvalidQuantity     = errors.New("Invalid quantity")
 )
 
 type ProductService struct {
-	database    *database.Database
-	authService *AuthService
+	database            *database.Database
+	authService         *AuthService
+	walletService       *WalletService
+	storeLedgerService  *StoreLedgerService
+	exchangeRateService *ExchangeRateService
+	addressService      *AddressService
+	categoryService     *CategoryService
 }
 
-func NewProductService(database *database.Database, auAuthService *AuthService) *ProductService {
+func NewProductService(
+	database *database.Database,
+	auAuthService *AuthService,
+	walletService *WalletService,
+	storeLedgerService *StoreLedgerService,
+	exchangeRateService *ExchangeRateService,
+	addressService *AddressService,
+	categoryService *CategoryService,
+) *ProductService {
 	return &ProductService{
-		database:    database,
-		authService: auAuthService,
+		database:            database,
+		authService:         auAuthService,
+		walletService:       walletService,
+		storeLedgerService:  storeLedgerService,
+		exchangeRateService: exchangeRateService,
+		addressService:      addressService,
+		categoryService:     categoryService,
 	}
 }
 
-func (s *ProductService) Buy(transactionRequest model.TransactionCreate, echoContext echo.Context) (model.Transaction, error) {
+func (s *ProductService) Buy(transactionRequest model.TransactionCreate, principal helper.Principal) (model.Transaction, error) {
 	var transaction model.Transaction
-	user, err := s.authService.CurrentUser(echoContext)
+	if transactionRequest.Quantity <= 0 {
+		return transaction, ErrInvalidQuantity
+	}
+
+	user, err := s.authService.CurrentUser(principal)
+	if err != nil {
+		return transaction, err
+	}
+
+	if !user.IsEmailVerified() {
+		return transaction, ErrEmailNotVerified
+	}
+
+	tx, err := s.database.Conn.Begin()
 	if err != nil {
 		return transaction, err
 	}
@@ -41,44 +72,55 @@ func (s *ProductService) Buy(transactionRequest model.TransactionCreate, echoCon
 		ID: transactionRequest.ProductID,
 	}
 
-	if err := product.GetByID(s.database.Conn); err != nil {
+	if err := product.GetByIDForUpdate(tx); err != nil {
+		tx.Rollback()
 		return transaction, ErrProductNotFound
 	}
 
 	store := model.Store{ID: product.StoreID}
-	if err := store.GetByID(s.database.Conn); err != nil {
+	if err := store.GetByID(tx); err != nil {
+		tx.Rollback()
 		return transaction, err
 	}
 
 	if store.OwnerEmail == user.Email {
+		tx.Rollback()
 		return transaction, ErrBuyYourOwnProduct
 	}
 
 	if product.Stock < transactionRequest.Quantity {
+		tx.Rollback()
 		return transaction, ErrInsufficientStock
 	}
 
-	valueTransaction := product.Price * int64(transactionRequest.Quantity)
+	transaction = transactionRequest.ToTransaction()
+	transaction.UserEmail = user.Email
+	transaction.ProductAmount = product.Price.Multiply(int64(transaction.Quantity))
+	transaction.Commission = s.storeLedgerService.commissionFor(transaction.ProductAmount.Amount)
 
-	if valueTransaction > user.Balance {
-		return transaction, ErrInsufficientBalance
+	transaction.Amount, transaction.ExchangeRate, err = s.exchangeRateService.convert(tx, transaction.ProductAmount, user.Balance.Currency)
+	if err != nil {
+		tx.Rollback()
+		return transaction, err
 	}
 
-	tx, err := s.database.Conn.Begin()
+	transaction.ShippingAddress, err = s.addressService.snapshot(tx, user.Email, transactionRequest.AddressID)
 	if err != nil {
+		tx.Rollback()
 		return transaction, err
 	}
 
-	user.Balance -= valueTransaction
-	if err := user.UpdateBalance(tx); err != nil {
+	if err := transaction.Create(tx); err != nil {
 		tx.Rollback()
 		return transaction, err
 	}
 
-	transaction = transactionRequest.ToTransaction()
-	transaction.UserEmail = user.Email
+	if _, err := s.walletService.post(tx, user.Email, model.LedgerEntryPurchase, -transaction.Amount.Amount, transaction.ID, "Purchase of "+product.Name); err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
 
-	if err := transaction.Create(tx); err != nil {
+	if err := s.storeLedgerService.creditSale(tx, store.ID, transaction); err != nil {
 		tx.Rollback()
 		return transaction, err
 	}
@@ -96,20 +138,20 @@ func (s *ProductService) Buy(transactionRequest model.TransactionCreate, echoCon
 	return transaction, nil
 }
 
-func (s *ProductService) Create(createRequest model.ProductCreate, echoContext echo.Context) (model.Product, error) {
+func (s *ProductService) Create(createRequest model.ProductCreate, principal helper.Principal) (model.Product, error) {
 	product := createRequest.ToProduct()
 
-	store := model.Store{
-		OwnerEmail: helper.ExtractJwtEmail(echoContext),
-	}
-	if err := store.GetByOwnerEmail(s.database.Conn); err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			return product, ErrDontHaveStore
-		}
+	store, err := s.currentStore(principal)
+	if err != nil {
 		return product, err
 	}
 
 	product.StoreID = store.ID
+	product.Price.Currency = store.Currency
+
+	if err := s.checkCategory(product.CategoryID); err != nil {
+		return product, err
+	}
 
 	if err := product.Create(s.database.Conn); err != nil {
 		return product, err
@@ -118,17 +160,17 @@ func (s *ProductService) Create(createRequest model.ProductCreate, echoContext e
 	return product, nil
 }
 
-func (s *ProductService) Update(updateRequest model.ProductUpdate, echoContext echo.Context) (model.Product, error) {
-	product := updateRequest.ToProduct()
+func (s *ProductService) Update(updateRequest model.ProductUpdate, principal helper.Principal) (model.Product, error) {
+	product := model.Product{
+		ID: updateRequest.ID,
+	}
 	if err := product.GetByID(s.database.Conn); err != nil {
 		return product, ErrProductNotFound
 	}
 
-	store := model.Store{
-		OwnerEmail: helper.ExtractJwtEmail(echoContext),
-	}
-	if err := store.GetByOwnerEmail(s.database.Conn); err != nil {
-		if err.Error() == "sql: no rows in result set" {
+	store, err := s.currentStore(principal)
+	if err != nil {
+		if err == ErrDontHaveStore {
 			return product, ErrDontOwnProduct
 		}
 		return product, err
@@ -138,9 +180,51 @@ func (s *ProductService) Update(updateRequest model.ProductUpdate, echoContext e
 		return product, ErrDontOwnProduct
 	}
 
+	updateRequest.ApplyTo(&product)
+
+	if err := s.checkCategory(product.CategoryID); err != nil {
+		return product, err
+	}
+
 	if err := product.UpdateByID(s.database.Conn); err != nil {
 		return product, err
 	}
 
 	return product, nil
 }
+
+func (s *ProductService) checkCategory(categoryID *string) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	_, err := s.categoryService.Get(*categoryID)
+	return err
+}
+
+func (s *ProductService) GetAllCurrentStoreProduct(principal helper.Principal) ([]model.Product, error) {
+	store, err := s.currentStore(principal)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.GetAllProductByStoreID(s.database.Conn, store.ID)
+}
+
+func (s *ProductService) currentStore(principal helper.Principal) (model.Store, error) {
+	store := model.Store{
+		ID: principal.StoreID,
+	}
+	if store.ID == "" {
+		return store, ErrDontHaveStore
+	}
+
+	if err := store.GetByID(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return store, ErrDontHaveStore
+		}
+		return store, err
+	}
+
+	return store, nil
+}
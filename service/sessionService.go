@@ -0,0 +1,74 @@
+package service
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/denylist"
+	"ecommerce-api/model"
+	"errors"
+)
+
+var ErrSessionNotFound = errors.New("Session not found")
+
+type SessionService struct {
+	database *database.Database
+	denylist *denylist.Denylist
+}
+
+func NewSessionService(database *database.Database, denylist *denylist.Denylist) *SessionService {
+	return &SessionService{
+		database: database,
+		denylist: denylist,
+	}
+}
+
+func (s *SessionService) GetAllByUserEmail(email string, currentToken string) ([]model.Session, error) {
+	sessions, err := model.GetAllActiveSessionByUserEmail(s.database.Conn, email)
+	if err != nil {
+		return sessions, err
+	}
+
+	if currentToken == "" {
+		return sessions, nil
+	}
+
+	refreshToken := model.RefreshToken{
+		Token: currentToken,
+	}
+	if err := refreshToken.GetByToken(s.database.Conn); err != nil {
+		return sessions, nil
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == refreshToken.FamilyID
+	}
+
+	return sessions, nil
+}
+
+func (s *SessionService) Revoke(email string, sessionID string) error {
+	session := model.Session{
+		ID:        sessionID,
+		UserEmail: email,
+	}
+	if err := session.GetByIDAndUserEmail(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	if err := s.denylist.RevokeSessions(session.ID); err != nil {
+		return err
+	}
+
+	return session.Delete(s.database.Conn)
+}
+
+func (s *SessionService) RevokeAll(email string) error {
+	sessionIDs, err := model.DeleteAllSessionByUserEmail(s.database.Conn, email)
+	if err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeSessions(sessionIDs...)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"errors"
+)
+
+var ErrApiKeyNotFound = errors.New("API key not found")
+
+type StoreApiKeyService struct {
+	database *database.Database
+}
+
+func NewStoreApiKeyService(database *database.Database) *StoreApiKeyService {
+	return &StoreApiKeyService{
+		database: database,
+	}
+}
+
+func (s *StoreApiKeyService) Create(email string, createRequest model.StoreApiKeyCreate) (model.StoreApiKey, error) {
+	apiKey := createRequest.ToStoreApiKey()
+
+	store, err := s.ownerStore(email)
+	if err != nil {
+		return apiKey, err
+	}
+
+	key, prefix, err := helper.GenerateApiKey()
+	if err != nil {
+		return apiKey, err
+	}
+
+	apiKey.StoreID = store.ID
+	apiKey.Prefix = prefix
+	apiKey.KeyHash = helper.HashToken(key)
+
+	if err := apiKey.Create(s.database.Conn); err != nil {
+		return apiKey, err
+	}
+
+	apiKey.Key = key
+
+	return apiKey, nil
+}
+
+func (s *StoreApiKeyService) GetAll(email string) ([]model.StoreApiKey, error) {
+	store, err := s.ownerStore(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.GetAllStoreApiKeyByStoreID(s.database.Conn, store.ID)
+}
+
+func (s *StoreApiKeyService) Revoke(email string, apiKeyID string) (model.StoreApiKey, error) {
+	var apiKey model.StoreApiKey
+
+	store, err := s.ownerStore(email)
+	if err != nil {
+		return apiKey, err
+	}
+
+	apiKey.ID = apiKeyID
+	apiKey.StoreID = store.ID
+	if err := apiKey.RevokeByIDAndStoreID(s.database.Conn); err != nil {
+		return apiKey, ErrApiKeyNotFound
+	}
+
+	return apiKey, nil
+}
+
+func (s *StoreApiKeyService) ownerStore(email string) (model.Store, error) {
+	store := model.Store{
+		OwnerEmail: email,
+	}
+	if err := store.GetByOwnerEmail(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return store, ErrDontHaveStore
+		}
+		return store, err
+	}
+
+	return store, nil
+}
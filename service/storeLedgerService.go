@@ -0,0 +1,149 @@
+package service
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidCommission             = errors.New("PLATFORM_COMMISSION_BPS must be between 0 and 10000")
+	ErrOpeningBalanceNotFound        = errors.New("Opening balance not found")
+	ErrOpeningBalanceAlreadyReleased = errors.New("Opening balance already released")
+)
+
+type SettlementPolicy struct {
+	HoldPeriod    time.Duration
+	CommissionBps int64
+}
+
+func (p SettlementPolicy) Validate() error {
+	if p.CommissionBps < 0 || p.CommissionBps > 10000 {
+		return ErrInvalidCommission
+	}
+
+	return nil
+}
+
+type StoreLedgerService struct {
+	database *database.Database
+	policy   SettlementPolicy
+}
+
+func NewStoreLedgerService(database *database.Database, policy SettlementPolicy) *StoreLedgerService {
+	return &StoreLedgerService{
+		database: database,
+		policy:   policy,
+	}
+}
+
+func (s *StoreLedgerService) GetBalance(principal helper.Principal) (model.StoreBalance, error) {
+	balance := model.StoreBalance{
+		StoreID: principal.StoreID,
+	}
+	if balance.StoreID == "" {
+		return balance, ErrDontHaveStore
+	}
+
+	if err := balance.GetByStoreID(s.database.Conn); err != nil {
+		return balance, err
+	}
+
+	return balance, nil
+}
+
+func (s *StoreLedgerService) GetEarnings(principal helper.Principal) (model.StoreEarnings, error) {
+	earnings := model.StoreEarnings{
+		StoreID: principal.StoreID,
+	}
+	if earnings.StoreID == "" {
+		return earnings, ErrDontHaveStore
+	}
+
+	if err := earnings.GetByStoreID(s.database.Conn); err != nil {
+		return earnings, err
+	}
+
+	return earnings, nil
+}
+
+func (s *StoreLedgerService) ReleaseOpeningBalance(storeID string) (model.StoreLedgerEntry, error) {
+	var entry model.StoreLedgerEntry
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return entry, err
+	}
+
+	openingBalance := model.StoreOpeningBalance{
+		StoreID: storeID,
+	}
+	if err := openingBalance.GetByStoreIDForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return entry, ErrOpeningBalanceNotFound
+		}
+		return entry, err
+	}
+
+	if openingBalance.IsReleased() {
+		tx.Rollback()
+		return entry, ErrOpeningBalanceAlreadyReleased
+	}
+
+	commission := s.commissionFor(openingBalance.GrossAmount)
+	entry = model.StoreLedgerEntry{
+		StoreID:     storeID,
+		Type:        model.StoreLedgerEntryOpeningBalance,
+		GrossAmount: openingBalance.GrossAmount,
+		Commission:  commission,
+		Amount:      openingBalance.GrossAmount - commission,
+	}
+	if err := entry.Create(tx, 0); err != nil {
+		tx.Rollback()
+		return entry, err
+	}
+
+	if err := openingBalance.Release(tx); err != nil {
+		tx.Rollback()
+		return entry, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+}
+
+func (s *StoreLedgerService) commissionFor(amount int64) int64 {
+	return amount * s.policy.CommissionBps / 10000
+}
+
+func (s *StoreLedgerService) creditSale(dbConn model.DBConn, storeID string, transaction model.Transaction) error {
+	entry := model.StoreLedgerEntry{
+		StoreID:     storeID,
+		Type:        model.StoreLedgerEntrySale,
+		GrossAmount: transaction.ProductAmount.Amount,
+		Commission:  transaction.Commission,
+		Amount:      transaction.ProductAmount.Amount - transaction.Commission,
+		ReferenceID: &transaction.ID,
+	}
+
+	return entry.Create(dbConn, s.policy.HoldPeriod)
+}
+
+func (s *StoreLedgerService) reverseSale(dbConn model.DBConn, storeID string, transaction model.Transaction) error {
+	entry := model.StoreLedgerEntry{
+		StoreID:     storeID,
+		Type:        model.StoreLedgerEntryRefund,
+		GrossAmount: -transaction.ProductAmount.Amount,
+		Commission:  -transaction.Commission,
+		Amount:      -(transaction.ProductAmount.Amount - transaction.Commission),
+		ReferenceID: &transaction.ID,
+	}
+
+	return entry.Create(dbConn, 0)
+}
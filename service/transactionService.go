@@ -0,0 +1,89 @@
+package service
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/model"
+	"errors"
+)
+
+var (
+	ErrTransactionNotFound        = errors.New("Transaction not found")
+	ErrTransactionAlreadyRefunded = errors.New("Transaction already refunded")
+)
+
+type TransactionService struct {
+	database           *database.Database
+	walletService      *WalletService
+	storeLedgerService *StoreLedgerService
+}
+
+func NewTransactionService(
+	database *database.Database,
+	walletService *WalletService,
+	storeLedgerService *StoreLedgerService,
+) *TransactionService {
+	return &TransactionService{
+		database:           database,
+		walletService:      walletService,
+		storeLedgerService: storeLedgerService,
+	}
+}
+
+func (s *TransactionService) Refund(transactionID string) (model.Transaction, error) {
+	transaction := model.Transaction{
+		ID: transactionID,
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return transaction, err
+	}
+
+	if err := transaction.GetByIDForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return transaction, ErrTransactionNotFound
+		}
+		return transaction, err
+	}
+
+	if transaction.RefundedAt != nil {
+		tx.Rollback()
+		return transaction, ErrTransactionAlreadyRefunded
+	}
+
+	product := model.Product{
+		ID: transaction.ProductID,
+	}
+	if err := product.GetByIDForUpdate(tx); err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+
+	if _, err := s.walletService.post(tx, transaction.UserEmail, model.LedgerEntryRefund, transaction.Amount.Amount, transaction.ID, "Refund of "+product.Name); err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+
+	if err := s.storeLedgerService.reverseSale(tx, product.StoreID, transaction); err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+
+	product.Stock += transaction.Quantity
+	if err := product.UpdateByID(tx); err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+
+	if err := transaction.MarkRefunded(tx); err != nil {
+		tx.Rollback()
+		return transaction, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return transaction, err
+	}
+
+	return transaction, nil
+}
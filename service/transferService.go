@@ -0,0 +1,142 @@
+package service
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/money"
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrTransferToYourself    = errors.New("Transfer to yourself")
+	ErrRecipientNotFound     = errors.New("Recipient not found")
+	ErrTransferLimitExceeded = errors.New("Daily transfer limit exceeded")
+	ErrIdempotencyKeyReused  = errors.New("Idempotency key reused with different parameters")
+)
+
+type TransferPolicy struct {
+	DailyLimit int64
+}
+
+type TransferService struct {
+	database      *database.Database
+	walletService *WalletService
+	policy        TransferPolicy
+}
+
+func NewTransferService(database *database.Database, walletService *WalletService, policy TransferPolicy) *TransferService {
+	return &TransferService{
+		database:      database,
+		walletService: walletService,
+		policy:        policy,
+	}
+}
+
+func (s *TransferService) Transfer(principal helper.Principal, createRequest model.TransferCreate) (model.Transfer, bool, error) {
+	transfer := createRequest.ToTransfer()
+	transfer.SenderEmail = principal.Email
+
+	if strings.EqualFold(transfer.SenderEmail, transfer.RecipientEmail) {
+		return transfer, false, ErrTransferToYourself
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return transfer, false, err
+	}
+
+	emails := []string{transfer.SenderEmail, transfer.RecipientEmail}
+	sort.Strings(emails)
+
+	users := map[string]model.User{}
+	for _, email := range emails {
+		user := model.User{
+			Email: email,
+		}
+		if err := user.GetByEmailForUpdate(tx); err != nil {
+			tx.Rollback()
+			if err.Error() == "sql: no rows in result set" {
+				if email == transfer.RecipientEmail {
+					return transfer, false, ErrRecipientNotFound
+				}
+				return transfer, false, ErrUserNotFound
+			}
+			return transfer, false, err
+		}
+		users[email] = user
+	}
+
+	existing := model.Transfer{
+		SenderEmail:    transfer.SenderEmail,
+		IdempotencyKey: transfer.IdempotencyKey,
+	}
+	if err := existing.GetBySenderEmailAndIdempotencyKey(tx); err == nil {
+		tx.Rollback()
+		if existing.RecipientEmail != transfer.RecipientEmail || existing.Amount != transfer.Amount {
+			return transfer, false, ErrIdempotencyKeyReused
+		}
+		return existing, true, nil
+	} else if err.Error() != "sql: no rows in result set" {
+		tx.Rollback()
+		return transfer, false, err
+	}
+
+	sender := users[transfer.SenderEmail]
+	if !sender.IsEmailVerified() {
+		tx.Rollback()
+		return transfer, false, ErrEmailNotVerified
+	}
+
+	recipient := users[transfer.RecipientEmail]
+	if recipient.DeletedAt != nil || recipient.IsBanned() {
+		tx.Rollback()
+		return transfer, false, ErrRecipientNotFound
+	}
+
+	if sender.Balance.Currency != recipient.Balance.Currency {
+		tx.Rollback()
+		return transfer, false, money.ErrCurrencyMismatch
+	}
+	transfer.Currency = sender.Balance.Currency
+
+	if s.policy.DailyLimit > 0 {
+		total, err := model.GetTodayTransferTotalBySenderEmail(tx, transfer.SenderEmail)
+		if err != nil {
+			tx.Rollback()
+			return transfer, false, err
+		}
+
+		if total+transfer.Amount > s.policy.DailyLimit {
+			tx.Rollback()
+			return transfer, false, ErrTransferLimitExceeded
+		}
+	}
+
+	if err := transfer.Create(tx); err != nil {
+		tx.Rollback()
+		return transfer, false, err
+	}
+
+	if _, err := s.walletService.post(tx, transfer.SenderEmail, model.LedgerEntryTransferOut, -transfer.Amount, transfer.ID, "Transfer sent"); err != nil {
+		tx.Rollback()
+		return transfer, false, err
+	}
+
+	if _, err := s.walletService.post(tx, transfer.RecipientEmail, model.LedgerEntryTransferIn, transfer.Amount, transfer.ID, "Transfer received"); err != nil {
+		tx.Rollback()
+		return transfer, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return transfer, false, err
+	}
+
+	return transfer, false, nil
+}
+
+func (s *TransferService) GetAllByUserEmail(email string) ([]model.Transfer, error) {
+	return model.GetAllTransferByUserEmail(s.database.Conn, email)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("Two-factor authentication already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("Two-factor authentication not enrolled")
+	ErrTwoFactorNotEnabled     = errors.New("Two-factor authentication not enabled")
+	ErrInvalidTwoFactorCode    = errors.New("Invalid two-factor code")
+)
+
+type TwoFactorService struct {
+	database *database.Database
+	issuer   string
+}
+
+func NewTwoFactorService(database *database.Database, issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		database: database,
+		issuer:   issuer,
+	}
+}
+
+func (s *TwoFactorService) IsEnabled(email string) (bool, error) {
+	userTotp := model.UserTotp{
+		UserEmail: email,
+	}
+	if err := userTotp.GetByUserEmail(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return userTotp.IsEnabled(), nil
+}
+
+func (s *TwoFactorService) Enroll(email string) (model.TotpEnrollment, error) {
+	var enrollment model.TotpEnrollment
+
+	enabled, err := s.IsEnabled(email)
+	if err != nil {
+		return enrollment, err
+	}
+
+	if enabled {
+		return enrollment, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := helper.GenerateTotpSecret()
+	if err != nil {
+		return enrollment, err
+	}
+
+	userTotp := model.UserTotp{
+		UserEmail: email,
+		Secret:    secret,
+	}
+	if err := userTotp.Upsert(s.database.Conn); err != nil {
+		return enrollment, err
+	}
+
+	enrollment.Secret = secret
+	enrollment.ProvisioningUri = helper.TotpProvisioningUri(s.issuer, email, secret)
+
+	return enrollment, nil
+}
+
+func (s *TwoFactorService) Confirm(email string, confirmRequest model.TotpConfirm) ([]string, error) {
+	var recoveryCodes []string
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return recoveryCodes, err
+	}
+
+	userTotp := model.UserTotp{
+		UserEmail: email,
+	}
+	if err := userTotp.GetByUserEmailForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return recoveryCodes, ErrTwoFactorNotEnrolled
+		}
+		return recoveryCodes, err
+	}
+
+	if userTotp.IsEnabled() {
+		tx.Rollback()
+		return recoveryCodes, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := helper.ValidateTotp(userTotp.Secret, confirmRequest.Code, time.Now())
+	if !ok {
+		tx.Rollback()
+		return recoveryCodes, ErrInvalidTwoFactorCode
+	}
+
+	userTotp.LastUsedStep = step
+	if err := userTotp.Enable(tx); err != nil {
+		tx.Rollback()
+		return recoveryCodes, err
+	}
+
+	if err := model.DeleteAllTotpRecoveryCodeByUserEmail(tx, email); err != nil {
+		tx.Rollback()
+		return recoveryCodes, err
+	}
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := helper.GenerateRecoveryCode()
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		recoveryCode := model.TotpRecoveryCode{
+			UserEmail: email,
+			CodeHash:  helper.HashToken(code),
+		}
+		if err := recoveryCode.Create(tx); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		recoveryCodes = append(recoveryCodes, code)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (s *TwoFactorService) Disable(email string, disableRequest model.TotpDisable) error {
+	user := model.User{
+		Email: email,
+	}
+	if err := user.GetByEmail(s.database.Conn); err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(disableRequest.Password)); err != nil {
+		return ErrWrongPassword
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := s.verifyCode(tx, email, disableRequest.Code); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	userTotp := model.UserTotp{
+		UserEmail: email,
+	}
+	if err := userTotp.Delete(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := model.DeleteAllTotpRecoveryCodeByUserEmail(tx, email); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *TwoFactorService) verifyCode(dbConn model.DBConn, email string, code string) error {
+	userTotp := model.UserTotp{
+		UserEmail: email,
+	}
+	if err := userTotp.GetByUserEmailForUpdate(dbConn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+
+	if !userTotp.IsEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if step, ok := helper.ValidateTotp(userTotp.Secret, code, time.Now()); ok {
+		if step <= userTotp.LastUsedStep {
+			return ErrInvalidTwoFactorCode
+		}
+
+		userTotp.LastUsedStep = step
+		return userTotp.UpdateLastUsedStep(dbConn)
+	}
+
+	recoveryCode := model.TotpRecoveryCode{
+		UserEmail: email,
+		CodeHash:  helper.HashToken(strings.ToLower(strings.TrimSpace(code))),
+	}
+	if err := recoveryCode.Consume(dbConn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	return nil
+}
@@ -1,23 +1,51 @@
 package service
 
 import (
+	"context"
 	"ecommerce-api/database"
+	"ecommerce-api/denylist"
+	"ecommerce-api/helper"
+	"ecommerce-api/mailer"
 	"ecommerce-api/model"
 	"errors"
+	"fmt"
+	"log"
+	"time"
 
 	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var ErrEmailAlreadyTaken = errors.New("Email already taken")
+var (
+	ErrEmailAlreadyTaken    = errors.New("Email already taken")
+	ErrInvalidResetToken    = errors.New("Invalid or expired reset token")
+	ErrInvalidVerifyToken   = errors.New("Invalid or expired verification token")
+	ErrEmailAlreadyVerified = errors.New("Email already verified")
+	ErrWrongPassword        = errors.New("Current password is incorrect")
+	ErrUserNotFound         = errors.New("User not found")
+)
 
 type UserService struct {
-	database *database.Database
+	database      *database.Database
+	denylist      *denylist.Denylist
+	mailer        mailer.Mailer
+	avatarService *AvatarService
+	appUrl        string
 }
 
-func NewUserService(database *database.Database) *UserService {
+func NewUserService(
+	database *database.Database,
+	denylist *denylist.Denylist,
+	mailer mailer.Mailer,
+	avatarService *AvatarService,
+	appUrl string,
+) *UserService {
 	return &UserService{
-		database: database,
+		database:      database,
+		denylist:      denylist,
+		mailer:        mailer,
+		avatarService: avatarService,
+		appUrl:        appUrl,
 	}
 }
 
@@ -38,5 +66,514 @@ func (s *UserService) Register(registerRequest model.UserRegister) (model.User,
 		return user, err
 	}
 
+	if err := s.sendVerificationEmail(user); err != nil {
+		log.Println(err)
+	}
+
 	return user, nil
 }
+
+func (s *UserService) GrantAdminRoles(emails []string) error {
+	for _, email := range emails {
+		user := model.User{
+			Email: email,
+		}
+		if err := user.GetByEmail(s.database.Conn); err != nil {
+			if err.Error() == "sql: no rows in result set" {
+				log.Printf("admin bootstrap: %s has not registered yet, restart after they sign up", email)
+				continue
+			}
+			return err
+		}
+
+		if user.DeletedAt != nil {
+			log.Printf("admin bootstrap: %s has deleted their account", email)
+			continue
+		}
+
+		if err := user.AddRole(s.database.Conn, model.RoleAdmin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *UserService) ResendVerificationEmail(email string) error {
+	user := model.User{
+		Email: email,
+	}
+	if err := user.GetByEmail(s.database.Conn); err != nil {
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.sendVerificationEmail(user)
+}
+
+func (s *UserService) VerifyEmail(verifyRequest model.EmailVerify) (model.User, error) {
+	var user model.User
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return user, err
+	}
+
+	verificationToken := model.EmailVerificationToken{
+		TokenHash: helper.HashToken(verifyRequest.Token),
+	}
+	if err := verificationToken.GetByTokenHashForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return user, ErrInvalidVerifyToken
+		}
+		return user, err
+	}
+
+	if verificationToken.IsUsed() || verificationToken.IsExpired() {
+		tx.Rollback()
+		return user, ErrInvalidVerifyToken
+	}
+
+	user.Email = verificationToken.UserEmail
+	if err := user.MarkEmailVerified(tx); err != nil {
+		tx.Rollback()
+		return user, err
+	}
+
+	if err := model.InvalidateEmailVerificationTokensByUserEmail(tx, user.Email); err != nil {
+		tx.Rollback()
+		return user, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) ChangePassword(email string, changeRequest model.UserPasswordChange, currentToken string) error {
+	user := model.User{
+		Email: email,
+	}
+	if err := user.GetByEmail(s.database.Conn); err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(changeRequest.CurrentPassword)); err != nil {
+		return ErrWrongPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(changeRequest.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	var currentSessionID string
+	if currentToken != "" {
+		refreshToken := model.RefreshToken{
+			Token: currentToken,
+		}
+		if err := refreshToken.GetByToken(s.database.Conn); err == nil && refreshToken.UserEmail == user.Email {
+			currentSessionID = refreshToken.FamilyID
+		}
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	user.Password = string(hashedPassword)
+	if err := user.UpdatePassword(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	sessionIDs, err := model.DeleteAllSessionByUserEmailExcept(tx, user.Email, currentSessionID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeSessions(sessionIDs...)
+}
+
+func (s *UserService) sendVerificationEmail(user model.User) error {
+	token, err := helper.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := model.InvalidateEmailVerificationTokensByUserEmail(tx, user.Email); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	verificationToken := model.EmailVerificationToken{
+		TokenHash: helper.HashToken(token),
+		UserEmail: user.Email,
+	}
+	if err := verificationToken.Create(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in one day.\n\n%s/verify-email?token=%s",
+			user.FirstName,
+			s.appUrl,
+			token,
+		),
+	})
+}
+
+func (s *UserService) ForgotPassword(forgotRequest model.PasswordForgot) error {
+	user := model.User{
+		Email: forgotRequest.Email,
+	}
+	if err := user.GetByEmail(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil
+		}
+		return err
+	}
+
+	token, err := helper.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := model.InvalidatePasswordResetTokensByUserEmail(tx, user.Email); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	resetToken := model.PasswordResetToken{
+		TokenHash: helper.HashToken(token),
+		UserEmail: user.Email,
+	}
+	if err := resetToken.Create(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to reset your password. It expires in one hour and can only be used once.\n\n%s/reset-password?token=%s\n\nIf you did not request a password reset, you can ignore this email.",
+			user.FirstName,
+			s.appUrl,
+			token,
+		),
+	}); err != nil {
+		log.Println(err)
+	}
+
+	return nil
+}
+
+func (s *UserService) ResetPassword(resetRequest model.PasswordReset) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(resetRequest.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	resetToken := model.PasswordResetToken{
+		TokenHash: helper.HashToken(resetRequest.Token),
+	}
+	if err := resetToken.GetByTokenHashForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if resetToken.IsUsed() || resetToken.IsExpired() {
+		tx.Rollback()
+		return ErrInvalidResetToken
+	}
+
+	user := model.User{
+		Email:    resetToken.UserEmail,
+		Password: string(hashedPassword),
+	}
+	if err := user.UpdatePassword(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := resetToken.MarkUsed(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	sessionIDs, err := model.DeleteAllSessionByUserEmail(tx, user.Email)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeSessions(sessionIDs...)
+}
+
+func (s *UserService) Ban(email string) (model.User, error) {
+	user := model.User{
+		Email: email,
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return user, err
+	}
+
+	if err := user.Ban(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return user, ErrUserNotFound
+		}
+		return user, err
+	}
+
+	sessionIDs, err := model.DeleteAllSessionByUserEmail(tx, user.Email)
+	if err != nil {
+		tx.Rollback()
+		return user, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return user, err
+	}
+
+	if err := s.denylist.RevokeSessions(sessionIDs...); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) Unban(email string) (model.User, error) {
+	user := model.User{
+		Email: email,
+	}
+
+	if err := user.Unban(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return user, ErrUserNotFound
+		}
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) Export(email string) (model.UserExport, error) {
+	export := model.UserExport{
+		User: model.User{
+			Email: email,
+		},
+		ExportedAt: time.Now().UTC(),
+	}
+
+	if err := export.User.GetByEmail(s.database.Conn); err != nil {
+		return export, err
+	}
+
+	store := model.Store{
+		OwnerEmail: email,
+	}
+	if err := store.GetByOwnerEmail(s.database.Conn); err == nil {
+		export.Store = &store
+
+		products, err := model.GetAllProductByStoreID(s.database.Conn, store.ID)
+		if err != nil {
+			return export, err
+		}
+		export.Products = products
+	} else if err.Error() != "sql: no rows in result set" {
+		return export, err
+	}
+
+	transactions, err := model.GetAllTransactionByUserEmail(s.database.Conn, email)
+	if err != nil {
+		return export, err
+	}
+	export.Transactions = transactions
+
+	transfers, err := model.GetAllTransferByUserEmail(s.database.Conn, email)
+	if err != nil {
+		return export, err
+	}
+	export.Transfers = transfers
+
+	ledgerEntries, err := model.GetAllWalletLedgerEntryByUserEmail(s.database.Conn, email)
+	if err != nil {
+		return export, err
+	}
+	export.LedgerEntries = ledgerEntries
+
+	addresses, err := model.GetAllAddressByUserEmail(s.database.Conn, email)
+	if err != nil {
+		return export, err
+	}
+	export.Addresses = addresses
+
+	sessions, err := model.GetAllActiveSessionByUserEmail(s.database.Conn, email)
+	if err != nil {
+		return export, err
+	}
+	export.Sessions = sessions
+
+	return export, nil
+}
+
+func (s *UserService) Delete(ctx context.Context, principal helper.Principal) error {
+	user := model.User{
+		Email: principal.Email,
+	}
+	if err := user.GetByEmail(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	avatar := user.Avatar
+
+	anonymousID, err := helper.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
+	anonymousEmail := "deleted-" + anonymousID[:16] + "@deleted.invalid"
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	store := model.Store{
+		OwnerEmail: user.Email,
+	}
+	if err := store.GetByOwnerEmail(tx); err == nil {
+		if err := model.DelistAllProductByStoreID(tx, store.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := model.RevokeAllStoreApiKeyByStoreID(tx, store.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	} else if err.Error() != "sql: no rows in result set" {
+		tx.Rollback()
+		return err
+	}
+
+	sessionIDs, err := model.DeleteAllSessionByUserEmail(tx, user.Email)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := user.DeleteCredentials(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	loginAttempt := model.LoginAttempt{
+		Key: loginEmailKey(user.Email),
+	}
+	if err := loginAttempt.Delete(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := model.DeleteAllLoginLockoutByKey(tx, loginAttempt.Key); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := model.AnonymizeLoginLockoutUnlockedBy(tx, user.Email, anonymousEmail); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := model.AnonymizeWithdrawalReviewedBy(tx, user.Email, anonymousEmail); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := model.AnonymizeExchangeRateUpdatedBy(tx, user.Email, anonymousEmail); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := model.DeleteAllAddressByUserEmail(tx, user.Email); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := model.ClearShippingAddressByUserEmail(tx, user.Email); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := user.Anonymize(tx, anonymousEmail); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.avatarService.purge(ctx, avatar)
+
+	if err := s.denylist.RevokeToken(principal.TokenID); err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeSessions(sessionIDs...)
+}
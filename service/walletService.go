@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"ecommerce-api/database"
+	"ecommerce-api/model"
+	"ecommerce-api/payment"
+	"errors"
+	"log"
+)
+
+var (
+	ErrTopUpNotFound      = errors.New("Top-up not found")
+	ErrTopUpPending       = errors.New("Top-up payment is still pending")
+	ErrTopUpLimitExceeded = errors.New("Daily top-up limit exceeded")
+	ErrPaymentFailed      = errors.New("Payment failed")
+)
+
+type WalletPolicy struct {
+	TopUpDailyLimit int64
+}
+
+type WalletService struct {
+	database        *database.Database
+	paymentProvider payment.Provider
+	policy          WalletPolicy
+}
+
+func NewWalletService(database *database.Database, paymentProvider payment.Provider, policy WalletPolicy) *WalletService {
+	return &WalletService{
+		database:        database,
+		paymentProvider: paymentProvider,
+		policy:          policy,
+	}
+}
+
+func (s *WalletService) TopUp(ctx context.Context, email string, topUpRequest model.WalletTopUp) (model.WalletTopUpIntent, error) {
+	topUp := model.WalletTopUpIntent{
+		UserEmail: email,
+		Amount:    topUpRequest.Amount,
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return topUp, err
+	}
+
+	user := model.User{
+		Email: email,
+	}
+	if err := user.GetByEmailForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return topUp, ErrUserNotFound
+		}
+		return topUp, err
+	}
+	topUp.Currency = user.Balance.Currency
+
+	if s.policy.TopUpDailyLimit > 0 {
+		total, err := model.GetTodayWalletTopUpTotalByUserEmail(tx, email)
+		if err != nil {
+			tx.Rollback()
+			return topUp, err
+		}
+
+		if total+topUp.Amount > s.policy.TopUpDailyLimit {
+			tx.Rollback()
+			return topUp, ErrTopUpLimitExceeded
+		}
+	}
+
+	if err := topUp.Create(tx); err != nil {
+		tx.Rollback()
+		return topUp, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return topUp, err
+	}
+
+	intent, err := s.paymentProvider.CreateIntent(ctx, payment.Charge{
+		TopUpID:   topUp.ID,
+		UserEmail: topUp.UserEmail,
+		Amount:    topUp.Amount,
+		Currency:  topUp.Currency,
+	})
+	if err != nil {
+		log.Println(err)
+		if err := topUp.Complete(s.database.Conn, model.WalletTopUpFailed); err != nil {
+			return topUp, err
+		}
+		return topUp, ErrPaymentFailed
+	}
+
+	topUp.ProviderReference = &intent.Reference
+	if intent.CheckoutUrl != "" {
+		topUp.CheckoutUrl = &intent.CheckoutUrl
+	}
+	if err := topUp.UpdateProviderIntent(s.database.Conn); err != nil {
+		return topUp, err
+	}
+
+	return topUp, nil
+}
+
+func (s *WalletService) ConfirmTopUp(ctx context.Context, email string, topUpID string) (model.WalletTopUpIntent, error) {
+	topUp := model.WalletTopUpIntent{
+		ID:        topUpID,
+		UserEmail: email,
+	}
+	if err := topUp.GetByIDAndUserEmail(s.database.Conn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return topUp, ErrTopUpNotFound
+		}
+		return topUp, err
+	}
+
+	if topUp.Status != model.WalletTopUpPending {
+		return topUp, nil
+	}
+
+	if topUp.ProviderReference == nil {
+		return topUp, ErrTopUpPending
+	}
+
+	intent, err := s.paymentProvider.GetIntent(ctx, *topUp.ProviderReference)
+	if err != nil {
+		log.Println(err)
+		return topUp, ErrPaymentFailed
+	}
+
+	if intent.Status == payment.StatusPending {
+		return topUp, ErrTopUpPending
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return topUp, err
+	}
+
+	if err := topUp.GetByIDForUpdate(tx); err != nil {
+		tx.Rollback()
+		return topUp, err
+	}
+
+	if topUp.Status != model.WalletTopUpPending {
+		tx.Rollback()
+		return topUp, nil
+	}
+
+	switch intent.Status {
+	case payment.StatusSucceeded:
+		if _, err := s.post(tx, topUp.UserEmail, model.LedgerEntryTopUp, topUp.Amount, topUp.ID, "Wallet top-up"); err != nil {
+			tx.Rollback()
+			return topUp, err
+		}
+
+		if err := topUp.Complete(tx, model.WalletTopUpSucceeded); err != nil {
+			tx.Rollback()
+			return topUp, err
+		}
+	default:
+		if err := topUp.Complete(tx, model.WalletTopUpFailed); err != nil {
+			tx.Rollback()
+			return topUp, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return topUp, err
+	}
+
+	return topUp, nil
+}
+
+func (s *WalletService) GetAllTopUp(email string) ([]model.WalletTopUpIntent, error) {
+	return model.GetAllWalletTopUpIntentByUserEmail(s.database.Conn, email)
+}
+
+func (s *WalletService) Adjust(email string, adjustmentRequest model.WalletAdjustment) (model.WalletLedgerEntry, error) {
+	return s.postInTransaction(email, model.LedgerEntryAdjustment, adjustmentRequest.Amount, "", adjustmentRequest.Description)
+}
+
+func (s *WalletService) GetAllLedgerEntry(email string) ([]model.WalletLedgerEntry, error) {
+	return model.GetAllWalletLedgerEntryByUserEmail(s.database.Conn, email)
+}
+
+func (s *WalletService) postInTransaction(email string, entryType string, amount int64, referenceID string, description string) (model.WalletLedgerEntry, error) {
+	var entry model.WalletLedgerEntry
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return entry, err
+	}
+
+	entry, err = s.post(tx, email, entryType, amount, referenceID, description)
+	if err != nil {
+		tx.Rollback()
+		return entry, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+}
+
+func (s *WalletService) post(dbConn model.DBConn, email string, entryType string, amount int64, referenceID string, description string) (model.WalletLedgerEntry, error) {
+	entry := model.WalletLedgerEntry{
+		UserEmail:   email,
+		Type:        entryType,
+		Amount:      amount,
+		Description: description,
+	}
+	if referenceID != "" {
+		entry.ReferenceID = &referenceID
+	}
+
+	user := model.User{
+		Email: email,
+	}
+	if err := user.GetByEmailForUpdate(dbConn); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return entry, ErrUserNotFound
+		}
+		return entry, err
+	}
+
+	if user.Balance.Amount+amount < 0 {
+		return entry, ErrInsufficientBalance
+	}
+
+	user.Balance.Amount += amount
+	if err := user.UpdateBalance(dbConn); err != nil {
+		return entry, err
+	}
+
+	entry.BalanceAfter = user.Balance.Amount
+	if err := entry.Create(dbConn); err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"ecommerce-api/database"
+	"ecommerce-api/helper"
+	"ecommerce-api/model"
+	"ecommerce-api/payout"
+	"errors"
+	"log"
+)
+
+var (
+	ErrWithdrawalNotFound    = errors.New("Withdrawal not found")
+	ErrWithdrawalNotPending  = errors.New("Withdrawal is not pending")
+	ErrWithdrawalNotApproved = errors.New("Withdrawal is not approved")
+	ErrPayoutFailed          = errors.New("Payout failed")
+)
+
+type WithdrawalService struct {
+	database       *database.Database
+	payoutProvider payout.Provider
+}
+
+func NewWithdrawalService(database *database.Database, payoutProvider payout.Provider) *WithdrawalService {
+	return &WithdrawalService{
+		database:       database,
+		payoutProvider: payoutProvider,
+	}
+}
+
+func (s *WithdrawalService) Request(principal helper.Principal, createRequest model.WithdrawalCreate) (model.Withdrawal, error) {
+	withdrawal := createRequest.ToWithdrawal()
+	withdrawal.StoreID = principal.StoreID
+	if withdrawal.StoreID == "" {
+		return withdrawal, ErrDontHaveStore
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return withdrawal, err
+	}
+
+	store := model.Store{
+		ID: withdrawal.StoreID,
+	}
+	if err := store.GetByIDForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return withdrawal, ErrDontHaveStore
+		}
+		return withdrawal, err
+	}
+
+	balance := model.StoreBalance{
+		StoreID: store.ID,
+	}
+	if err := balance.GetByStoreID(tx); err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	if withdrawal.Amount > balance.Available {
+		tx.Rollback()
+		return withdrawal, ErrInsufficientBalance
+	}
+
+	if err := withdrawal.Create(tx); err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	entry := model.StoreLedgerEntry{
+		StoreID:     store.ID,
+		Type:        model.StoreLedgerEntryWithdrawal,
+		Amount:      -withdrawal.Amount,
+		ReferenceID: &withdrawal.ID,
+	}
+	if err := entry.Create(tx, 0); err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return withdrawal, err
+	}
+
+	return withdrawal, nil
+}
+
+func (s *WithdrawalService) GetAllCurrent(principal helper.Principal) ([]model.Withdrawal, error) {
+	if principal.StoreID == "" {
+		return nil, ErrDontHaveStore
+	}
+
+	return model.GetAllWithdrawalByStoreID(s.database.Conn, principal.StoreID)
+}
+
+func (s *WithdrawalService) GetAll(status string) ([]model.Withdrawal, error) {
+	return model.GetAllWithdrawal(s.database.Conn, status)
+}
+
+func (s *WithdrawalService) Approve(ctx context.Context, withdrawalID string, reviewedBy string) (model.Withdrawal, error) {
+	withdrawal := model.Withdrawal{
+		ID: withdrawalID,
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return withdrawal, err
+	}
+
+	if err := withdrawal.GetByIDForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return withdrawal, ErrWithdrawalNotFound
+		}
+		return withdrawal, err
+	}
+
+	switch withdrawal.Status {
+	case model.WithdrawalRequested:
+		if err := withdrawal.Approve(tx, reviewedBy); err != nil {
+			tx.Rollback()
+			return withdrawal, err
+		}
+	case model.WithdrawalApproved:
+	default:
+		tx.Rollback()
+		return withdrawal, ErrWithdrawalNotPending
+	}
+
+	store := model.Store{
+		ID: withdrawal.StoreID,
+	}
+	if err := store.GetByID(tx); err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return withdrawal, err
+	}
+
+	reference, err := s.payoutProvider.Send(ctx, payout.Payout{
+		WithdrawalID:   withdrawal.ID,
+		IdempotencyKey: withdrawal.ID,
+		StoreID:        store.ID,
+		Recipient:      store.OwnerEmail,
+		Amount:         withdrawal.Amount,
+	})
+	if err != nil {
+		log.Println(err)
+		return withdrawal, ErrPayoutFailed
+	}
+
+	tx, err = s.database.Conn.Begin()
+	if err != nil {
+		return withdrawal, err
+	}
+
+	if err := withdrawal.GetByIDForUpdate(tx); err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	switch withdrawal.Status {
+	case model.WithdrawalApproved:
+		if err := withdrawal.MarkPaid(tx, reference); err != nil {
+			tx.Rollback()
+			return withdrawal, err
+		}
+	case model.WithdrawalPaid:
+	default:
+		tx.Rollback()
+		log.Printf("payout %s was sent for withdrawal %s which is now %s", reference, withdrawal.ID, withdrawal.Status)
+		return withdrawal, ErrWithdrawalNotApproved
+	}
+
+	if err := tx.Commit(); err != nil {
+		return withdrawal, err
+	}
+
+	return withdrawal, nil
+}
+
+func (s *WithdrawalService) Reject(withdrawalID string, reviewedBy string, rejectRequest model.WithdrawalReject) (model.Withdrawal, error) {
+	withdrawal := model.Withdrawal{
+		ID: withdrawalID,
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return withdrawal, err
+	}
+
+	if err := withdrawal.GetByIDForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return withdrawal, ErrWithdrawalNotFound
+		}
+		return withdrawal, err
+	}
+
+	if withdrawal.Status != model.WithdrawalRequested {
+		tx.Rollback()
+		return withdrawal, ErrWithdrawalNotPending
+	}
+
+	if err := withdrawal.Reject(tx, reviewedBy, rejectRequest.Reason); err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	if err := s.reverse(tx, withdrawal); err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return withdrawal, err
+	}
+
+	return withdrawal, nil
+}
+
+func (s *WithdrawalService) MarkFailed(withdrawalID string, failRequest model.WithdrawalFail) (model.Withdrawal, error) {
+	withdrawal := model.Withdrawal{
+		ID: withdrawalID,
+	}
+
+	tx, err := s.database.Conn.Begin()
+	if err != nil {
+		return withdrawal, err
+	}
+
+	if err := withdrawal.GetByIDForUpdate(tx); err != nil {
+		tx.Rollback()
+		if err.Error() == "sql: no rows in result set" {
+			return withdrawal, ErrWithdrawalNotFound
+		}
+		return withdrawal, err
+	}
+
+	if withdrawal.Status != model.WithdrawalApproved {
+		tx.Rollback()
+		return withdrawal, ErrWithdrawalNotApproved
+	}
+
+	if err := withdrawal.MarkFailed(tx, failRequest.Reason); err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	if err := s.reverse(tx, withdrawal); err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return withdrawal, err
+	}
+
+	return withdrawal, nil
+}
+
+func (s *WithdrawalService) reverse(dbConn model.DBConn, withdrawal model.Withdrawal) error {
+	entry := model.StoreLedgerEntry{
+		StoreID:     withdrawal.StoreID,
+		Type:        model.StoreLedgerEntryWithdrawalReversal,
+		Amount:      withdrawal.Amount,
+		ReferenceID: &withdrawal.ID,
+	}
+
+	return entry.Create(dbConn, 0)
+}
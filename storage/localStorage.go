@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type LocalStorage struct {
+	dir       string
+	publicUrl string
+}
+
+func NewLocalStorage(dir string, publicUrl string) *LocalStorage {
+	return &LocalStorage{
+		dir:       dir,
+		publicUrl: strings.TrimSuffix(publicUrl, "/"),
+	}
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	temporaryPath := path + ".tmp"
+	if err := os.WriteFile(temporaryPath, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryPath, path)
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrObjectNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.publicUrl + "/" + key
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+
+	relative, err := filepath.Rel(s.dir, path)
+	if err != nil || relative == "." || strings.HasPrefix(relative, "..") {
+		return "", errors.New("invalid object key")
+	}
+
+	return path, nil
+}
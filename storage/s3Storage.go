@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type S3Storage struct {
+	client       *http.Client
+	endpoint     *url.URL
+	region       string
+	bucket       string
+	accessKey    string
+	secretKey    string
+	usePathStyle bool
+	publicUrl    string
+	now          func() time.Time
+}
+
+func NewS3Storage(config Config, client *http.Client) *S3Storage {
+	if client == nil {
+		client = &http.Client{
+			Timeout: 30 * time.Second,
+		}
+	}
+
+	endpoint, err := url.Parse(config.S3Endpoint)
+	if err != nil || endpoint.Host == "" {
+		endpoint = &url.URL{
+			Scheme: "https",
+			Host:   "s3." + config.S3Region + ".amazonaws.com",
+		}
+	}
+
+	return &S3Storage{
+		client:       client,
+		endpoint:     endpoint,
+		region:       config.S3Region,
+		bucket:       config.S3Bucket,
+		accessKey:    config.S3AccessKey,
+		secretKey:    config.S3SecretKey,
+		usePathStyle: config.S3UsePathStyle,
+		publicUrl:    strings.TrimSuffix(config.PublicUrl, "/"),
+		now:          time.Now,
+	}
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectUrl(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	request.ContentLength = int64(len(data))
+	request.Header.Set("Content-Type", contentType)
+	s.sign(request, data)
+
+	return s.do(request)
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectUrl(key), nil)
+	if err != nil {
+		return err
+	}
+
+	s.sign(request, nil)
+
+	return s.do(request)
+}
+
+func (s *S3Storage) URL(key string) string {
+	if s.publicUrl != "" {
+		return s.publicUrl + "/" + key
+	}
+	return s.objectUrl(key)
+}
+
+func (s *S3Storage) objectUrl(key string) string {
+	objectUrl := *s.endpoint
+	if s.usePathStyle {
+		objectUrl.Path = "/" + s.bucket + "/" + key
+	} else {
+		objectUrl.Host = s.bucket + "." + objectUrl.Host
+		objectUrl.Path = "/" + key
+	}
+
+	return objectUrl.String()
+}
+
+func (s *S3Storage) do(request *http.Request) error {
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return ErrObjectNotFound
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("s3 %s %s: %s: %s", request.Method, request.URL.Path, response.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+func (s *S3Storage) sign(request *http.Request, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(payload)
+	contentHash := hex.EncodeToString(payloadHash[:])
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", contentHash)
+
+	headerNames := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if request.Header.Get("Content-Type") != "" {
+		headerNames = append(headerNames, "content-type")
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		value := request.Header.Get(name)
+		if name == "host" {
+			value = request.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		contentHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSha256([]byte("AWS4"+s.secretKey), date)
+	signingKey = hmacSha256(signingKey, s.region)
+	signingKey = hmacSha256(signingKey, "s3")
+	signingKey = hmacSha256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
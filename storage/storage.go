@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+const LocalPublicPath = "/media"
+
+var ErrObjectNotFound = errors.New("Object not found")
+
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+type Config struct {
+	Driver         string
+	LocalDir       string
+	PublicUrl      string
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3UsePathStyle bool
+}
+
+func NewStorage(config Config) Storage {
+	switch config.Driver {
+	case "s3":
+		return NewS3Storage(config, nil)
+	default:
+		if config.PublicUrl == "" {
+			return NewLocalStorage(config.LocalDir, LocalPublicPath)
+		}
+		return NewLocalStorage(config.LocalDir, config.PublicUrl)
+	}
+}